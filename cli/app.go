@@ -2,8 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"math/big"
 	"os"
 
+	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/server"
 	"github.com/dcb9/janus/pkg/transformer"
@@ -16,11 +18,40 @@ import (
 var (
 	app = kingpin.New("janus", "Qtum adapter to Ethereum JSON RPC")
 
-	qtumRPC     = app.Flag("qtum-rpc", "URL of qtum RPC service").Envar("QTUM_RPC").Default("").String()
+	qtumRPC     = app.Flag("qtum-rpc", "URL of qtum RPC service, wallet calls are only sent to it").Envar("QTUM_RPC").Default("").String()
 	qtumNetwork = app.Flag("qtum-network", "").Envar("QTUM_NETWORK").Default("regtest").String()
+	chainID     = app.Flag("chain-id", "chain ID eth_chainId and net_version report, 0 means the default of the network: 81 (main), 8889 (test), 4132 (regtest)").Envar("JANUS_CHAIN_ID").Default("0").Int64()
 	bind        = app.Flag("bind", "network interface to bind to (e.g. 0.0.0.0) ").Default("localhost").String()
 	port        = app.Flag("port", "port to serve proxy").Default("23889").Int()
 	devMode     = app.Flag("dev", "[Insecure] Developer mode").Default("false").Bool()
+	maxTipAge   = app.Flag("max-tip-age", "/readyz fails if the tip of the chain is older, 0 disables the check").Default("1h").Duration()
+
+	automine       = app.Flag("automine", "mine a block after each transaction on regtest").Default("true").Bool()
+	miningInterval = app.Flag("mining-interval", "mine a block at this interval on regtest, 0 disables it").Default("0").Duration()
+
+	weiPerSatoshi = app.Flag("wei-per-satoshi", "how many wei of Ethereum clients are a satoshi, e.g. 10000000000 makes 1 QTUM 1e18 wei like 1 ETH").Default("1").Int64()
+
+	authConfig       = app.Flag("auth-config", "JSON file of the API keys and the JWT secret clients authenticate with, see server.AuthConfig").Envar("JANUS_AUTH_CONFIG").Default("").String()
+	maxBatchSize     = app.Flag("max-batch-size", "maximum number of requests in a batch, 0 means unlimited").Default("100").Int()
+	batchConcurrency = app.Flag("batch-concurrency", "how many requests of a batch are processed at once").Default("10").Int()
+
+	rateLimit        = app.Flag("rate-limit", "requests per second each client IP or API key can make, eth_getLogs counts as 10 requests and eth_feeHistory as 1 per 10 blocks, 0 disables it").Default("0").Float64()
+	rateLimitBurst   = app.Flag("rate-limit-burst", "how many requests a client can make at once").Default("100").Int()
+	maxBodySize      = app.Flag("max-body-size", "maximum size of HTTP request bodies in bytes, 0 means unlimited").Default("5242880").Int64()
+	filterTimeout    = app.Flag("filter-timeout", "filters which are not polled for this long are uninstalled, 0 disables it").Default("5m").Duration()
+	maxFilters       = app.Flag("max-filters", "maximum number of filters each client IP or API key can install, 0 means unlimited").Default("100").Int()
+	maxLogBlockRange = app.Flag("max-log-block-range", "maximum number of blocks eth_getLogs, and filters of logs at each poll, can search, 0 means unlimited").Default("0").Int64()
+
+	qtumTimeout      = app.Flag("qtum-timeout", "timeout of a call to qtumd, 0 disables it").Envar("QTUM_TIMEOUT").Default("30s").Duration()
+	qtumRetries      = app.Flag("qtum-retries", "how many times read calls to qtumd are retried after connection errors").Envar("QTUM_RETRIES").Default("2").Int()
+	qtumRetryBackoff = app.Flag("qtum-retry-backoff", "delay before the first retry, it doubles after each retry").Envar("QTUM_RETRY_BACKOFF").Default("100ms").Duration()
+
+	qtumReadRPCs            = app.Flag("qtum-read-rpc", "URL of another qtumd node read calls are spread over, repeatable").Envar("QTUM_READ_RPC").Strings()
+	qtumHealthCheckInterval = app.Flag("qtum-health-check-interval", "how often the qtumd nodes are checked").Default("5s").Duration()
+	qtumMaxLag              = app.Flag("qtum-max-lag", "how many blocks a qtumd node can be behind the best node to get read calls").Default("2").Int64()
+
+	qtumCacheSize          = app.Flag("qtum-cache-size", "how many results of qtumd calls about confirmed blocks and transactions are cached, 0 disables the cache").Envar("QTUM_CACHE_SIZE").Default("10000").Int()
+	qtumCacheConfirmations = app.Flag("qtum-cache-confirmations", "how many confirmations blocks and transactions need to be cached").Envar("QTUM_CACHE_CONFIRMATIONS").Default("6").Int64()
 )
 
 func action(pc *kingpin.ParseContext) error {
@@ -31,22 +62,61 @@ func action(pc *kingpin.ParseContext) error {
 		logger = level.NewFilter(logger, level.AllowWarn())
 	}
 
-	qtumJSONRPC, err := qtum.NewClient(*qtumRPC, qtum.SetDebug(*devMode), qtum.SetLogger(logger))
+	qtumJSONRPC, err := qtum.NewClient(
+		*qtumRPC,
+		qtum.SetDebug(*devMode),
+		qtum.SetLogger(logger),
+		qtum.SetTimeout(*qtumTimeout),
+		qtum.SetRetries(*qtumRetries),
+		qtum.SetRetryBackoff(*qtumRetryBackoff),
+		qtum.SetCacheSize(*qtumCacheSize),
+		qtum.SetCacheConfirmations(*qtumCacheConfirmations),
+		qtum.SetUpstreams(*qtumReadRPCs...),
+		qtum.SetHealthCheckInterval(*qtumHealthCheckInterval),
+		qtum.SetMaxLag(*qtumMaxLag),
+	)
 	if err != nil {
 		return errors.Wrap(err, "jsonrpc#New")
 	}
 
-	qtumClient, err := qtum.New(qtumJSONRPC, *qtumNetwork)
+	qtumClient, err := qtum.New(qtumJSONRPC, *qtumNetwork, qtum.SetChainID(*chainID), qtum.SetWeiPerSatoshi(big.NewInt(*weiPerSatoshi)))
 	if err != nil {
 		return errors.Wrap(err, "qtum#New")
 	}
 
-	t, err := transformer.New(qtumClient, transformer.DefaultProxies(qtumClient), transformer.SetDebug(*devMode), transformer.SetLogger(logger))
+	var miner *transformer.Miner
+	if qtumClient.Chain() == qtum.ChainRegTest {
+		miner = transformer.NewMiner(qtumClient, transformer.SetAutomine(*automine), transformer.SetMiningInterval(*miningInterval))
+		miner.Start()
+		defer miner.Stop()
+	} else if *devMode {
+		// only the evm_* methods which don't change the chain, see DevProxies
+		miner = transformer.NewMiner(qtumClient, transformer.SetAutomine(false))
+	}
+
+	proxies := transformer.DefaultProxies(qtumClient, miner, eth.SetFilterIdleTimeout(*filterTimeout), eth.SetMaxFiltersPerClient(*maxFilters),
+		eth.SetFilterMaxLogBlockRange(*maxLogBlockRange))
+	if miner != nil {
+		// the evm_* methods of Ganache, for contract test suites
+		proxies = append(proxies, transformer.DevProxies(miner)...)
+	}
+	t, err := transformer.New(qtumClient, proxies, transformer.SetDebug(*devMode), transformer.SetLogger(logger))
 	if err != nil {
 		return errors.Wrap(err, "transformer#New")
 	}
 
-	s, err := server.New(qtumClient, t, addr, server.SetLogger(logger), server.SetDebug(*devMode))
+	var auth *server.AuthConfig
+	if *authConfig != "" {
+		if auth, err = server.LoadAuthConfig(*authConfig); err != nil {
+			return errors.Wrap(err, "server#LoadAuthConfig")
+		}
+	} else if !*devMode {
+		level.Warn(logger).Log("msg", "authentication is disabled, anyone who can reach janus can spend the funds of the qtumd wallet")
+	}
+
+	s, err := server.New(qtumClient, t, addr, server.SetLogger(logger), server.SetDebug(*devMode), server.SetMaxTipAge(*maxTipAge),
+		server.SetMaxBatchSize(*maxBatchSize), server.SetBatchConcurrency(*batchConcurrency), server.SetAuth(auth),
+		server.SetRateLimit(*rateLimit, *rateLimitBurst), server.SetMaxBodySize(*maxBodySize), server.SetMaxLogBlockRange(*maxLogBlockRange))
 	if err != nil {
 		return errors.Wrap(err, "server#New")
 	}
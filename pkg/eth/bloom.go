@@ -0,0 +1,75 @@
+package eth
+
+import (
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+const BloomByteLength = 256
+
+// Bloom is the 2048 bits bloom filter of receipts and blocks,
+// see: yellow paper 4.3.1 Transaction Receipt
+type Bloom [BloomByteLength]byte
+
+// Add sets the 3 bits selected by keccak256(data)
+func (b *Bloom) Add(data []byte) {
+	h := Keccak256(data)
+	for i := 0; i < 6; i += 2 {
+		bit := (uint(h[i])<<8 | uint(h[i+1])) & 2047
+		b[BloomByteLength-1-bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Or merges other into b, the bloom of a block is the Or of its receipts' blooms
+func (b *Bloom) Or(other *Bloom) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+// Test reports whether data may have been added to b
+func (b *Bloom) Test(data []byte) bool {
+	var expected Bloom
+	expected.Add(data)
+	for i := range expected {
+		if b[i]&expected[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Bloom) Hex() string {
+	return hexutil.Encode(b[:])
+}
+
+// AddLog adds the address and topics of log
+func (b *Bloom) AddLog(log *Log) error {
+	address, err := hexutil.Decode(utils.AddHexPrefix(log.Address))
+	if err != nil {
+		return errors.Wrap(err, "decode log address")
+	}
+	b.Add(address)
+
+	for _, topic := range log.Topics {
+		t, err := hexutil.Decode(utils.AddHexPrefix(topic))
+		if err != nil {
+			return errors.Wrap(err, "decode log topic")
+		}
+		b.Add(t)
+	}
+
+	return nil
+}
+
+// LogsBloom returns the bloom of a receipt
+func LogsBloom(logs []Log) (*Bloom, error) {
+	var b Bloom
+	for i := range logs {
+		if err := b.AddLog(&logs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &b, nil
+}
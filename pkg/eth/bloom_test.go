@@ -0,0 +1,81 @@
+package eth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// vectors from go-ethereum core/types/bloom9_test.go
+
+func TestBloom(t *testing.T) {
+	positive := []string{
+		"testtest",
+		"test",
+		"hallo",
+		"other",
+	}
+	negative := []string{
+		"tes",
+		"lo",
+	}
+
+	var b Bloom
+	for _, data := range positive {
+		b.Add([]byte(data))
+	}
+
+	for _, data := range positive {
+		if !b.Test([]byte(data)) {
+			t.Error("expected", data, "to test true")
+		}
+	}
+	for _, data := range negative {
+		if b.Test([]byte(data)) {
+			t.Error("did not expect", data, "to test true")
+		}
+	}
+}
+
+func TestBloomExtensively(t *testing.T) {
+	exp := "0xc8d3ca65cdb4874300a9e39475508f23ed6da09fdbc487f89a2dcf50b09eb263"
+
+	var b Bloom
+	for i := 0; i < 100; i++ {
+		b.Add([]byte(fmt.Sprintf("xxxxxxxxxx data %d yyyyyyyyyyyyyy", i)))
+	}
+
+	if got := hexutil.Encode(Keccak256(b[:])); got != exp {
+		t.Errorf("want: %s, got: %s", exp, got)
+	}
+}
+
+func TestLogsBloom(t *testing.T) {
+	logs := []Log{
+		{
+			Address: "0x90f3e8062c8537ee4825fd384caef0260795f8df",
+			Topics: []string{
+				"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+				"0x000000000000000000000000cb3cb8375fe457a11f041f9ff55373e1a5a78d19",
+			},
+		},
+	}
+
+	b, err := LogsBloom(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range []string{logs[0].Address, logs[0].Topics[0], logs[0].Topics[1]} {
+		data, _ := hexutil.Decode(item)
+		if !b.Test(data) {
+			t.Errorf("expected %s to be in the bloom", item)
+		}
+	}
+
+	var empty Bloom
+	if b.Hex() == empty.Hex() {
+		t.Error("bloom must not be empty")
+	}
+}
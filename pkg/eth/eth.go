@@ -1,8 +1,12 @@
 package eth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -14,6 +18,36 @@ type JSONRPCRequest struct {
 	Method  string          `json:"method"`
 	ID      json.RawMessage `json:"id"`
 	Params  json.RawMessage `json:"params"`
+
+	ctx context.Context
+}
+
+// Context returns the context of the request, it is canceled when the client goes away
+func (r *JSONRPCRequest) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of r with its context changed to ctx
+func (r *JSONRPCRequest) WithContext(ctx context.Context) *JSONRPCRequest {
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of ctx which identifies the client of the request, e.g. by its API key or its IP
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client set by WithClient, it is empty if the client is unknown
+func ClientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
 }
 
 type JSONRPCResult struct {
@@ -23,15 +57,100 @@ type JSONRPCResult struct {
 	ID        json.RawMessage `json:"id"`
 }
 
+// JSONRPCNotification is a server initiated message without id, e.g. eth_subscription
+type JSONRPCNotification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// Error codes, see: https://www.jsonrpc.org/specification#error_object
+// and https://eips.ethereum.org/EIPS/eip-1474#error-codes
+const (
+	ErrCodeParse          = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternal       = -32603
+	ErrCodeExecution      = -32000
+
+	// the requested resource is unavailable, e.g. the state of a past block
+	ErrCodeResourceUnavailable = -32002
+	// the method exists, but the key of the client is not allowed to call it
+	ErrCodeMethodNotAllowed = -32004
+	// the client exceeded a rate or size limit
+	ErrCodeLimitExceeded = -32005
+
+	// the call was reverted, data is the revert payload
+	ErrCodeRevert = 3
+)
+
 type JSONRPCError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 func (err *JSONRPCError) Error() string {
 	return fmt.Sprintf("eth [code: %d] %s", err.Code, err.Message)
 }
 
+func NewParseError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeParse, Message: message}
+}
+
+func NewInvalidRequestError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeInvalidRequest, Message: message}
+}
+
+func NewMethodNotFoundError(method string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeMethodNotFound, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+}
+
+func NewMethodNotAllowedError(method string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeMethodNotAllowed, Message: fmt.Sprintf("the method %s is not allowed for this key", method)}
+}
+
+func NewResourceUnavailableError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeResourceUnavailable, Message: message}
+}
+
+func NewLimitExceededError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeLimitExceeded, Message: message}
+}
+
+func NewInvalidParamsError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeInvalidParams, Message: message}
+}
+
+func NewInternalError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeInternal, Message: message}
+}
+
+func NewExecutionError(message string) *JSONRPCError {
+	return &JSONRPCError{Code: ErrCodeExecution, Message: message}
+}
+
+// NewRevertError returns the error of a reverted call, data is the hex encoded output of the call.
+// The reason of Error(string) and Panic(uint256) payloads is appended to the message, like geth does.
+func NewRevertError(data string) *JSONRPCError {
+	message := "execution reverted"
+	if output, err := hexutil.Decode(data); err == nil {
+		if reason, ok := DecodeRevertReason(output); ok {
+			message += ": " + reason
+		}
+	}
+	return &JSONRPCError{Code: ErrCodeRevert, Message: message, Data: data}
+}
+
+// ToJSONRPCError returns the JSONRPCError that caused err, other errors are internal errors
+func ToJSONRPCError(err error) *JSONRPCError {
+	if jsonErr, ok := errors.Cause(err).(*JSONRPCError); ok {
+		return jsonErr
+	}
+	return NewInternalError(err.Error())
+}
+
 func NewJSONRPCResult(id json.RawMessage, res interface{}) (*JSONRPCResult, error) {
 	rawResult, err := json.Marshal(res)
 	if err != nil {
@@ -44,3 +163,16 @@ func NewJSONRPCResult(id json.RawMessage, res interface{}) (*JSONRPCResult, erro
 		RawResult: rawResult,
 	}, nil
 }
+
+func NewJSONRPCNotification(method string, params interface{}) (*JSONRPCNotification, error) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONRPCNotification{
+		JSONRPC: RPCVersion,
+		Method:  method,
+		Params:  rawParams,
+	}, nil
+}
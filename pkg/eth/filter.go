@@ -1,11 +1,17 @@
 package eth
 
 import (
+	"fmt"
 	"math/big"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/dcb9/janus/pkg/metrics"
 )
 
+var activeFilters = metrics.NewGauge("janus_filters_active", "Number of installed filters, including the ones of websocket subscriptions")
+
 type FilterType int
 
 const (
@@ -20,36 +26,172 @@ type Filter struct {
 	Request      interface{}
 	LastBlockNum *big.Int
 	Data         sync.Map
+
+	// the client which installed the filter
+	owner string
+	// unix nano time of the last poll
+	lastUsed int64
+	// 1 if the filter never expires, see Pin
+	pinned int32
+}
+
+// Pin keeps the filter from expiring, for filters which are not polled by clients but uninstalled by their owner,
+// e.g. the filters of websocket subscriptions
+func (f *Filter) Pin() {
+	atomic.StoreInt32(&f.pinned, 1)
+}
+
+func (f *Filter) touch(now time.Time) {
+	atomic.StoreInt64(&f.lastUsed, now.UnixNano())
+}
+
+func (f *Filter) idle(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&f.lastUsed)))
 }
 
 type FilterSimulator struct {
 	filters     sync.Map
 	maxFilterID *uint64
+
+	// filters which are not polled for idleTimeout are uninstalled, 0 means never
+	idleTimeout time.Duration
+	// the maximum number of filters of a client, 0 means unlimited
+	maxPerOwner int
+	// the maximum number of blocks a filter of logs searches, when it is installed and at each poll, 0 means unlimited
+	maxLogBlockRange int64
+
+	mutex     sync.Mutex
+	owners    map[string]int // number of filters per client
+	lastSweep time.Time
 }
 
-func NewFilterSimulator() *FilterSimulator {
+type FilterOption func(*FilterSimulator)
+
+// SetFilterIdleTimeout uninstalls filters which are not polled for timeout, 0 disables it
+func SetFilterIdleTimeout(timeout time.Duration) FilterOption {
+	return func(f *FilterSimulator) {
+		f.idleTimeout = timeout
+	}
+}
+
+// SetMaxFiltersPerClient limits the number of filters of a client, 0 means unlimited
+func SetMaxFiltersPerClient(max int) FilterOption {
+	return func(f *FilterSimulator) {
+		f.maxPerOwner = max
+	}
+}
+
+// SetFilterMaxLogBlockRange limits the number of blocks filters of logs search, 0 means unlimited.
+// Filters cannot be installed from further back, and each poll searches at most blocks blocks.
+func SetFilterMaxLogBlockRange(blocks int64) FilterOption {
+	return func(f *FilterSimulator) {
+		f.maxLogBlockRange = blocks
+	}
+}
+
+func NewFilterSimulator(opts ...FilterOption) *FilterSimulator {
 	id := uint64(0)
-	return &FilterSimulator{
+	f := &FilterSimulator{
 		maxFilterID: &id,
+		owners:      make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(f)
 	}
+
+	return f
 }
 
-func (f *FilterSimulator) New(ty FilterType, req ...interface{}) *Filter {
+// New installs a filter for owner, the client which sent the request, see ClientFromContext
+func (f *FilterSimulator) New(owner string, ty FilterType, req ...interface{}) (*Filter, error) {
+	now := time.Now()
+	f.sweep(now)
+
+	f.mutex.Lock()
+	if f.maxPerOwner > 0 && f.owners[owner] >= f.maxPerOwner {
+		f.mutex.Unlock()
+		return nil, NewLimitExceededError(fmt.Sprintf("too many filters, a client can install at most %d filters", f.maxPerOwner))
+	}
+	f.owners[owner]++
+	f.mutex.Unlock()
+
 	id := atomic.AddUint64(f.maxFilterID, 1)
-	filter := &Filter{ID: id, Type: ty}
+	filter := &Filter{ID: id, Type: ty, owner: owner}
 	if ty == NewFilterTy {
 		filter.Request = req[0]
 	}
+	filter.touch(now)
 
 	f.filters.Store(id, filter)
+	activeFilters.Inc()
+
+	return filter, nil
+}
 
-	return filter
+// MaxLogBlockRange returns the maximum number of blocks filters of logs search, 0 means unlimited
+func (f *FilterSimulator) MaxLogBlockRange() int64 {
+	return f.maxLogBlockRange
 }
 
 func (f *FilterSimulator) Uninstall(filterID uint64) {
-	f.filters.Delete(filterID)
+	value, ok := f.filters.LoadAndDelete(filterID)
+	if !ok {
+		return
+	}
+	activeFilters.Dec()
+
+	owner := value.(*Filter).owner
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.owners[owner]--; f.owners[owner] <= 0 {
+		delete(f.owners, owner)
+	}
 }
 
+// Filter returns the filter and keeps it from expiring, pinned filters are only polled by their owner and not returned
 func (f *FilterSimulator) Filter(filterID uint64) (value interface{}, ok bool) {
-	return f.filters.Load(filterID)
+	value, ok = f.filters.Load(filterID)
+	if !ok {
+		return nil, false
+	}
+
+	now := time.Now()
+	filter := value.(*Filter)
+	if atomic.LoadInt32(&filter.pinned) != 0 {
+		return nil, false
+	}
+	if f.expired(filter, now) {
+		f.Uninstall(filterID)
+		return nil, false
+	}
+	filter.touch(now)
+
+	return filter, true
+}
+
+func (f *FilterSimulator) expired(filter *Filter, now time.Time) bool {
+	return f.idleTimeout > 0 && atomic.LoadInt32(&filter.pinned) == 0 && filter.idle(now) > f.idleTimeout
+}
+
+// sweep uninstalls the expired filters, at most once per minute
+func (f *FilterSimulator) sweep(now time.Time) {
+	if f.idleTimeout <= 0 {
+		return
+	}
+
+	f.mutex.Lock()
+	if now.Sub(f.lastSweep) < time.Minute {
+		f.mutex.Unlock()
+		return
+	}
+	f.lastSweep = now
+	f.mutex.Unlock()
+
+	f.filters.Range(func(key, value interface{}) bool {
+		if f.expired(value.(*Filter), now) {
+			f.Uninstall(key.(uint64))
+		}
+		return true
+	})
 }
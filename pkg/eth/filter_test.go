@@ -0,0 +1,49 @@
+package eth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterSimulatorLimits(t *testing.T) {
+	f := NewFilterSimulator(SetFilterIdleTimeout(5*time.Minute), SetMaxFiltersPerClient(2))
+
+	a1, err := f.New("a", NewBlockFilterTy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.New("a", NewBlockFilterTy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.New("a", NewBlockFilterTy); err == nil {
+		t.Errorf("in: a, want: too many filters, got: nil")
+	}
+	if _, err := f.New("b", NewBlockFilterTy); err != nil {
+		t.Errorf("in: b, want: nil, got: %s", err)
+	}
+
+	// a filter which isn't polled expires, and it doesn't count anymore
+	a1.touch(time.Now().Add(-6 * time.Minute))
+	if _, ok := f.Filter(a1.ID); ok {
+		t.Errorf("in: %d, want: expired, got: found", a1.ID)
+	}
+	if _, err := f.New("a", NewBlockFilterTy); err != nil {
+		t.Errorf("in: a, want: nil, got: %s", err)
+	}
+
+	// a pinned filter, e.g. of a websocket subscription, survives the sweep
+	b1, err := f.New("b", NewBlockFilterTy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1.Pin()
+	b1.touch(time.Now().Add(-6 * time.Minute))
+	f.sweep(time.Now().Add(time.Hour))
+	if _, ok := f.filters.Load(b1.ID); !ok {
+		t.Errorf("in: %d, want: installed, got: expired", b1.ID)
+	}
+	// it is not polled by eth_getFilterChanges
+	if _, ok := f.Filter(b1.ID); ok {
+		t.Errorf("in: %d, want: not found, got: found", b1.ID)
+	}
+}
@@ -0,0 +1,159 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// RawTransaction is a signed legacy (optionally EIP-155) Ethereum transaction,
+// RLP([nonce, gasPrice, gas, to, value, data, v, r, s])
+type RawTransaction struct {
+	Nonce    *big.Int
+	GasPrice *big.Int
+	Gas      *big.Int
+	To       []byte // nil for contract creation
+	Value    *big.Int
+	Data     []byte
+	V        *big.Int
+	R        *big.Int
+	S        *big.Int
+}
+
+func DecodeRawTransaction(rawtx string) (*RawTransaction, error) {
+	data, err := hexutil.Decode(utils.AddHexPrefix(rawtx))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 && data[0] < 0xc0 {
+		return nil, errors.Errorf("typed transaction (type %d) is not supported", data[0])
+	}
+
+	item, err := RLPDecode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := item.([]interface{})
+	if !ok || len(fields) != 9 {
+		return nil, errors.New("transaction must be a list of 9 items")
+	}
+
+	var bytesFields [9][]byte
+	for i, field := range fields {
+		if bytesFields[i], ok = field.([]byte); !ok {
+			return nil, errors.Errorf("transaction item %d must be a string", i)
+		}
+	}
+
+	tx := &RawTransaction{
+		Data: bytesFields[5],
+	}
+
+	ints := []struct {
+		dst  **big.Int
+		data []byte
+	}{
+		{&tx.Nonce, bytesFields[0]},
+		{&tx.GasPrice, bytesFields[1]},
+		{&tx.Gas, bytesFields[2]},
+		{&tx.Value, bytesFields[4]},
+		{&tx.V, bytesFields[6]},
+		{&tx.R, bytesFields[7]},
+		{&tx.S, bytesFields[8]},
+	}
+	for _, i := range ints {
+		if len(i.data) > 32 || (len(i.data) > 0 && i.data[0] == 0) {
+			return nil, errors.New("transaction contains a non-canonical integer")
+		}
+		*i.dst = new(big.Int).SetBytes(i.data)
+	}
+
+	switch len(bytesFields[3]) {
+	case 0:
+	case 20:
+		tx.To = bytesFields[3]
+	default:
+		return nil, errors.New("transaction recipient must be 20 bytes")
+	}
+
+	return tx, nil
+}
+
+func (tx *RawTransaction) IsCreateContract() bool {
+	return tx.To == nil
+}
+
+// ChainID returns nil if the transaction is not protected by EIP-155
+func (tx *RawTransaction) ChainID() *big.Int {
+	if tx.V.Cmp(big.NewInt(35)) < 0 {
+		return nil
+	}
+
+	// v = chainID * 2 + 35 + {0, 1}
+	chainID := new(big.Int).Sub(tx.V, big.NewInt(35))
+	return chainID.Rsh(chainID, 1)
+}
+
+// Hash returns the transaction hash, i.e. keccak256 of the signed RLP
+func (tx *RawTransaction) Hash() ([]byte, error) {
+	encoded, err := RLPEncode(append(tx.unsignedFields(), tx.V, tx.R, tx.S))
+	if err != nil {
+		return nil, err
+	}
+	return Keccak256(encoded), nil
+}
+
+// Sender recovers the 20 bytes address which signed the transaction
+func (tx *RawTransaction) Sender() ([]byte, error) {
+	fields := tx.unsignedFields()
+
+	var recID *big.Int
+	if chainID := tx.ChainID(); chainID != nil {
+		fields = append(fields, chainID, uint64(0), uint64(0))
+		recID = new(big.Int).Sub(tx.V, new(big.Int).Lsh(chainID, 1))
+		recID.Sub(recID, big.NewInt(35))
+	} else {
+		recID = new(big.Int).Sub(tx.V, big.NewInt(27))
+	}
+
+	if recID.Sign() < 0 || recID.Cmp(big.NewInt(1)) > 0 {
+		return nil, errors.Wrap(InvalidSignatureErr, "invalid v")
+	}
+
+	// homestead: reject malleable signatures
+	if tx.S.Cmp(secp256k1HalfN) > 0 {
+		return nil, errors.Wrap(InvalidSignatureErr, "s is too large")
+	}
+
+	encoded, err := RLPEncode(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := ecrecover(Keccak256(encoded), tx.R, tx.S, byte(recID.Uint64()))
+	if err != nil {
+		return nil, err
+	}
+
+	return PubkeyToAddress(pub), nil
+}
+
+func (tx *RawTransaction) unsignedFields() []interface{} {
+	to := tx.To
+	if to == nil {
+		to = []byte{}
+	}
+
+	return []interface{}{
+		tx.Nonce,
+		tx.GasPrice,
+		tx.Gas,
+		to,
+		tx.Value,
+		tx.Data,
+	}
+}
@@ -0,0 +1,82 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// example from https://github.com/ethereum/EIPs/blob/master/EIPS/eip-155.md,
+// signed with private key 0x4646464646464646464646464646464646464646464646464646464646464646
+const eip155RawTx = "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+
+func TestDecodeRawTransaction(t *testing.T) {
+	tx, err := DecodeRawTransaction(eip155RawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"nonce", tx.Nonce.String(), "9"},
+		{"gasPrice", tx.GasPrice.String(), "20000000000"},
+		{"gas", tx.Gas.String(), "21000"},
+		{"to", hexutil.Encode(tx.To), "0x3535353535353535353535353535353535353535"},
+		{"value", tx.Value.String(), "1000000000000000000"},
+		{"data", hexutil.Encode(tx.Data), "0x"},
+		{"chainID", tx.ChainID().String(), "1"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("%s, want: %s, got: %s", c.name, c.want, c.got)
+		}
+	}
+
+	sender, err := tx.Sender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"; hexutil.Encode(sender) != want {
+		t.Errorf("sender, want: %s, got: %s", want, hexutil.Encode(sender))
+	}
+
+	hash, err := tx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788"; hexutil.Encode(hash) != want {
+		t.Errorf("hash, want: %s, got: %s", want, hexutil.Encode(hash))
+	}
+}
+
+func TestRLPRoundTrip(t *testing.T) {
+	long := make([]byte, 60)
+	in := []interface{}{[]byte("cat"), []interface{}{[]byte{}, []byte{0x7f}}, long}
+
+	encoded, err := RLPEncode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := RLPDecode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reencoded, err := RLPEncode(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hexutil.Encode(reencoded) != hexutil.Encode(encoded) {
+		t.Errorf("want: %x, got: %x", encoded, reencoded)
+	}
+
+	// "dog" from the RLP spec
+	encoded, _ = RLPEncode([]byte("dog"))
+	if want := "0x83646f67"; hexutil.Encode(encoded) != want {
+		t.Errorf("want: %s, got: %s", want, hexutil.Encode(encoded))
+	}
+}
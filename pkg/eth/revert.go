@@ -0,0 +1,79 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// the selectors of Error(string) and Panic(uint256), the revert payloads of solidity
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons are the meanings of the codes of Panic(uint256), like geth
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// DecodeRevertReason returns the reason of a revert payload, the message of Error(string) or the meaning of the code
+// of Panic(uint256), and false if the payload is neither
+func DecodeRevertReason(output []byte) (string, bool) {
+	if len(output) < 4 {
+		return "", false
+	}
+	selector, args := output[:4], output[4:]
+
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		// the offset of the string, then its length and its bytes padded to 32 bytes
+		offset, ok := abiUint(args, 0)
+		if !ok || offset > uint64(len(args)) {
+			return "", false
+		}
+		length, ok := abiUint(args, offset)
+		if !ok || length > uint64(len(args))-offset-32 {
+			return "", false
+		}
+		start := offset + 32
+		return string(args[start : start+length]), true
+	case bytes.Equal(selector, panicSelector):
+		if len(args) < 32 {
+			return "", false
+		}
+		code := new(big.Int).SetBytes(args[:32])
+		if code.IsUint64() {
+			if reason, ok := panicReasons[code.Uint64()]; ok {
+				return reason, true
+			}
+		}
+		return fmt.Sprintf("unknown panic code: %#x", code), true
+	}
+
+	return "", false
+}
+
+// abiUint decodes the uint256 at offset of ABI encoded data, false if it does not fit in a uint64
+func abiUint(data []byte, offset uint64) (uint64, bool) {
+	if offset > uint64(len(data)) || uint64(len(data))-offset < 32 {
+		return 0, false
+	}
+	word := data[offset : offset+32]
+	for _, b := range word[:24] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return binary.BigEndian.Uint64(word[24:]), true
+}
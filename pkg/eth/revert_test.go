@@ -0,0 +1,49 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestNewRevertError(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		// Error("Not enough balance")
+		{"0x08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000012" +
+			"4e6f7420656e6f7567682062616c616e63650000000000000000000000000000", "execution reverted: Not enough balance"},
+		// Panic(0x11)
+		{"0x4e487b710000000000000000000000000000000000000000000000000000000000000011", "execution reverted: arithmetic underflow or overflow"},
+		{"0x4e487b710000000000000000000000000000000000000000000000000000000000000099", "execution reverted: unknown panic code: 0x99"},
+		// a custom error
+		{"0xcf479181", "execution reverted"},
+		{"0x", "execution reverted"},
+		// the length of the string is past the end of the payload
+		{"0x08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000040" +
+			"4e6f7420656e6f7567682062616c616e63650000000000000000000000000000", "execution reverted"},
+	}
+
+	for _, c := range cases {
+		err := NewRevertError(c.in)
+		if err.Message != c.want {
+			t.Errorf("in: %s, want: %s, got: %s", c.in, c.want, err.Message)
+		}
+		if err.Data != c.in {
+			t.Errorf("in: %s, want data: %s, got: %v", c.in, c.in, err.Data)
+		}
+	}
+}
+
+func TestDecodeRevertReasonShort(t *testing.T) {
+	for _, in := range []string{"0x08c379a0", "0x08c379a000", "0x4e487b71"} {
+		if reason, ok := DecodeRevertReason(hexutil.MustDecode(in)); ok {
+			t.Errorf("in: %s, want: not decoded, got: %s", in, reason)
+		}
+	}
+}
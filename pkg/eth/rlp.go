@@ -0,0 +1,155 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// RLP is the serialization used by Ethereum transactions,
+// see: https://github.com/ethereum/wiki/wiki/RLP
+//
+// A decoded item is either a []byte (string) or a []interface{} (list).
+
+var RLPInvalidErr = errors.New("invalid RLP encoding")
+
+// RLPDecode decodes data which must contain exactly one item
+func RLPDecode(data []byte) (interface{}, error) {
+	item, rest, err := rlpDecodeItem(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.Wrap(RLPInvalidErr, "trailing bytes")
+	}
+
+	return item, nil
+}
+
+func rlpDecodeItem(data []byte) (item interface{}, rest []byte, err error) {
+	if len(data) == 0 {
+		return nil, nil, errors.Wrap(RLPInvalidErr, "unexpected end of data")
+	}
+
+	prefix := data[0]
+	switch {
+	case prefix < 0x80:
+		return data[:1], data[1:], nil
+
+	case prefix < 0xb8:
+		size := int(prefix - 0x80)
+		if len(data) < 1+size {
+			return nil, nil, errors.Wrap(RLPInvalidErr, "string is too short")
+		}
+		if size == 1 && data[1] < 0x80 {
+			return nil, nil, errors.Wrap(RLPInvalidErr, "single byte must be encoded as itself")
+		}
+		return data[1 : 1+size], data[1+size:], nil
+
+	case prefix < 0xc0:
+		offset, size, err := rlpLongSize(data, int(prefix-0xb7))
+		if err != nil {
+			return nil, nil, err
+		}
+		return data[offset : offset+size], data[offset+size:], nil
+
+	case prefix < 0xf8:
+		size := int(prefix - 0xc0)
+		if len(data) < 1+size {
+			return nil, nil, errors.Wrap(RLPInvalidErr, "list is too short")
+		}
+		list, err := rlpDecodeList(data[1 : 1+size])
+		return list, data[1+size:], err
+
+	default:
+		offset, size, err := rlpLongSize(data, int(prefix-0xf7))
+		if err != nil {
+			return nil, nil, err
+		}
+		list, err := rlpDecodeList(data[offset : offset+size])
+		return list, data[offset+size:], err
+	}
+}
+
+// rlpLongSize reads the size of a string or list longer than 55 bytes
+func rlpLongSize(data []byte, sizeOfSize int) (offset, size int, err error) {
+	if sizeOfSize > 4 || len(data) < 1+sizeOfSize {
+		return 0, 0, errors.Wrap(RLPInvalidErr, "invalid size")
+	}
+
+	if data[1] == 0 {
+		return 0, 0, errors.Wrap(RLPInvalidErr, "size has leading zero")
+	}
+
+	for _, b := range data[1 : 1+sizeOfSize] {
+		size = size<<8 | int(b)
+	}
+
+	if size < 56 {
+		return 0, 0, errors.Wrap(RLPInvalidErr, "size must use the short form")
+	}
+
+	offset = 1 + sizeOfSize
+	if len(data)-offset < size {
+		return 0, 0, errors.Wrap(RLPInvalidErr, "data is too short")
+	}
+
+	return offset, size, nil
+}
+
+func rlpDecodeList(data []byte) ([]interface{}, error) {
+	list := make([]interface{}, 0)
+	for len(data) > 0 {
+		item, rest, err := rlpDecodeItem(data)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+		data = rest
+	}
+	return list, nil
+}
+
+// RLPEncode encodes []byte, *big.Int, uint64 and []interface{} of those types
+func RLPEncode(item interface{}) ([]byte, error) {
+	switch v := item.(type) {
+	case []byte:
+		if len(v) == 1 && v[0] < 0x80 {
+			return []byte{v[0]}, nil
+		}
+		return append(rlpEncodeSize(len(v), 0x80), v...), nil
+
+	case *big.Int:
+		if v.Sign() < 0 {
+			return nil, errors.New("RLP cannot encode negative integer")
+		}
+		return RLPEncode(v.Bytes())
+
+	case uint64:
+		return RLPEncode(new(big.Int).SetUint64(v))
+
+	case []interface{}:
+		var payload []byte
+		for _, elem := range v {
+			encoded, err := RLPEncode(elem)
+			if err != nil {
+				return nil, err
+			}
+			payload = append(payload, encoded...)
+		}
+		return append(rlpEncodeSize(len(payload), 0xc0), payload...), nil
+
+	default:
+		return nil, errors.Errorf("RLP cannot encode %T", item)
+	}
+}
+
+func rlpEncodeSize(size int, offset byte) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+
+	sizeBytes := new(big.Int).SetInt64(int64(size)).Bytes()
+	return append([]byte{offset + 55 + byte(len(sizeBytes))}, sizeBytes...)
+}
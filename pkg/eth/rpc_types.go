@@ -62,6 +62,32 @@ func (t *SendTransactionRequest) GasPriceHex() string {
 	return t.GasPrice.Hex()
 }
 
+// ========== eth_sendRawTransaction ============= //
+
+type (
+	// the signed transaction data
+	SendRawTransactionRequest string
+
+	// the transaction hash
+	SendRawTransactionResponse string
+)
+
+func (r *SendRawTransactionRequest) UnmarshalJSON(data []byte) error {
+	var params []string
+	err := json.Unmarshal(data, &params)
+	if err != nil {
+		return err
+	}
+
+	if len(params) == 0 {
+		return errors.New("params must be set")
+	}
+
+	*r = SendRawTransactionRequest(params[0])
+
+	return nil
+}
+
 // CallResponse
 type CallResponse string
 
@@ -73,6 +99,9 @@ type CallRequest struct {
 	GasPrice *ETHInt `json:"gasPrice"` // optional
 	Value    string  `json:"value"`    // optional
 	Data     string  `json:"data"`     // optional
+
+	// the block number or tag of the state the call runs against, the second parameter
+	BlockNumber json.RawMessage `json:"-"`
 }
 
 func (t *CallRequest) GasHex() string {
@@ -107,6 +136,9 @@ func (t *CallRequest) UnmarshalJSON(data []byte) error {
 	}
 
 	cr := CallRequest(obj)
+	if len(params) > 1 {
+		cr.BlockNumber = params[1]
+	}
 	*t = cr
 	return nil
 }
@@ -115,8 +147,26 @@ type (
 	PersonalUnlockAccountResponse bool
 	BlockNumberResponse           string
 	NetVersionResponse            string
+	ChainIDResponse               string
+	NetListeningResponse          bool
+	NetPeerCountResponse          string
+	ProtocolVersionResponse       string
+	MiningResponse                bool
+	HashrateResponse              string
+	CoinbaseResponse              string
+	GasPriceResponse              string
+	MaxPriorityFeePerGasResponse  string
 )
 
+// ========== eth_syncing ============= //
+
+// SyncingResult is the result of eth_syncing while the node is syncing, otherwise the result is false
+type SyncingResult struct {
+	StartingBlock string `json:"startingBlock"` // QUANTITY - The block at which the import started (will only be reset, after the sync reached his head)
+	CurrentBlock  string `json:"currentBlock"`  // QUANTITY - The current block, same as eth_blockNumber
+	HighestBlock  string `json:"highestBlock"`  // QUANTITY - The estimated highest block
+}
+
 // ========== GetLogs ============= //
 
 type (
@@ -124,7 +174,7 @@ type (
 		FromBlock json.RawMessage `json:"fromBlock"`
 		ToBlock   json.RawMessage `json:"toBlock"`
 		Address   json.RawMessage `json:"address"` // string or []string
-		Topics    []string        `json:"topics"`
+		Topics    []interface{}   `json:"topics"`  // null, string or []string per position
 		Blockhash string          `json:"blockhash"`
 	}
 	GetLogsResponse []Log
@@ -184,31 +234,32 @@ func (r *GetTransactionByHashRequest) UnmarshalJSON(data []byte) error {
 type (
 	GetTransactionReceiptRequest  string
 	GetTransactionReceiptResponse struct {
-		TransactionHash   string `json:"transactionHash"`   // DATA, 32 Bytes - hash of the transaction.
-		TransactionIndex  string `json:"transactionIndex"`  // QUANTITY - integer of the transactions index position in the block.
-		BlockHash         string `json:"blockHash"`         // DATA, 32 Bytes - hash of the block where this transaction was in.
-		BlockNumber       string `json:"blockNumber"`       // QUANTITY - block number where this transaction was in.
-		From              string `json:"from,omitempty"`    // DATA, 20 Bytes - address of the sender.
-		To                string `json:"to,omitempty"`      // DATA, 20 Bytes - address of the receiver. null when its a contract creation transaction.
-		CumulativeGasUsed string `json:"cumulativeGasUsed"` // QUANTITY - The total amount of gas used when this transaction was executed in the block.
-		GasUsed           string `json:"gasUsed"`           // QUANTITY - The amount of gas used by this specific transaction alone.
-		ContractAddress   string `json:"contractAddress"`   // DATA, 20 Bytes - The contract address created, if the transaction was a contract creation, otherwise null.
-		Logs              []Log  `json:"logs"`              // Array - Array of log objects, which this transaction generated.
-		LogsBloom         string `json:"logsBloom"`         // DATA, 256 Bytes - Bloom filter for light clients to quickly retrieve related logs.
-		Root              string `json:"root,omitempty"`    // DATA 32 bytes of post-transaction stateroot (pre Byzantium)
-		Status            string `json:"status"`            // QUANTITY either 1 (success) or 0 (failure)
+		TransactionHash   string `json:"transactionHash"`        // DATA, 32 Bytes - hash of the transaction.
+		TransactionIndex  string `json:"transactionIndex"`       // QUANTITY - integer of the transactions index position in the block.
+		BlockHash         string `json:"blockHash"`              // DATA, 32 Bytes - hash of the block where this transaction was in.
+		BlockNumber       string `json:"blockNumber"`            // QUANTITY - block number where this transaction was in.
+		From              string `json:"from,omitempty"`         // DATA, 20 Bytes - address of the sender.
+		To                string `json:"to,omitempty"`           // DATA, 20 Bytes - address of the receiver. null when its a contract creation transaction.
+		CumulativeGasUsed string `json:"cumulativeGasUsed"`      // QUANTITY - The total amount of gas used when this transaction was executed in the block.
+		GasUsed           string `json:"gasUsed"`                // QUANTITY - The amount of gas used by this specific transaction alone.
+		ContractAddress   string `json:"contractAddress"`        // DATA, 20 Bytes - The contract address created, if the transaction was a contract creation, otherwise null.
+		Logs              []Log  `json:"logs"`                   // Array - Array of log objects, which this transaction generated.
+		LogsBloom         string `json:"logsBloom"`              // DATA, 256 Bytes - Bloom filter for light clients to quickly retrieve related logs.
+		Root              string `json:"root,omitempty"`         // DATA 32 bytes of post-transaction stateroot (pre Byzantium)
+		Status            string `json:"status"`                 // QUANTITY either 1 (success) or 0 (failure)
+		RevertReason      string `json:"revertReason,omitempty"` // the reason of the revert of a failed transaction, if it has one
 	}
 
 	Log struct {
-		Removed          string   `json:"removed,omitempty"` // TAG - true when the log was removed, due to a chain reorganization. false if its a valid log.
-		LogIndex         string   `json:"logIndex"`          // QUANTITY - integer of the log index position in the block. null when its pending log.
-		TransactionIndex string   `json:"transactionIndex"`  // QUANTITY - integer of the transactions index position log was created from. null when its pending log.
-		TransactionHash  string   `json:"transactionHash"`   // DATA, 32 Bytes - hash of the transactions this log was created from. null when its pending log.
-		BlockHash        string   `json:"blockHash"`         // DATA, 32 Bytes - hash of the block where this log was in. null when its pending. null when its pending log.
-		BlockNumber      string   `json:"blockNumber"`       // QUANTITY - the block number where this log was in. null when its pending. null when its pending log.
-		Address          string   `json:"address"`           // DATA, 20 Bytes - address from which this log originated.
-		Data             string   `json:"data"`              // DATA - contains one or more 32 Bytes non-indexed arguments of the log.
-		Topics           []string `json:"topics"`            // Array of DATA - Array of 0 to 4 32 Bytes DATA of indexed log arguments.
+		Removed          bool     `json:"removed"`          // TAG - true when the log was removed, due to a chain reorganization. false if its a valid log.
+		LogIndex         string   `json:"logIndex"`         // QUANTITY - integer of the log index position in the block. null when its pending log.
+		TransactionIndex string   `json:"transactionIndex"` // QUANTITY - integer of the transactions index position log was created from. null when its pending log.
+		TransactionHash  string   `json:"transactionHash"`  // DATA, 32 Bytes - hash of the transactions this log was created from. null when its pending log.
+		BlockHash        string   `json:"blockHash"`        // DATA, 32 Bytes - hash of the block where this log was in. null when its pending. null when its pending log.
+		BlockNumber      string   `json:"blockNumber"`      // QUANTITY - the block number where this log was in. null when its pending. null when its pending log.
+		Address          string   `json:"address"`          // DATA, 20 Bytes - address from which this log originated.
+		Data             string   `json:"data"`             // DATA - contains one or more 32 Bytes non-indexed arguments of the log.
+		Topics           []string `json:"topics"`           // Array of DATA - Array of 0 to 4 32 Bytes DATA of indexed log arguments.
 		Type             string   `json:"type,omitempty"`
 	}
 )
@@ -235,14 +286,14 @@ type AccountsResponse []string
 type (
 	GetCodeRequest struct {
 		Address     string
-		BlockNumber string
+		BlockNumber json.RawMessage
 	}
 	// the code from the given address.
 	GetCodeResponse string
 )
 
 func (r *GetCodeRequest) UnmarshalJSON(data []byte) error {
-	var params []string
+	var params []json.RawMessage
 	err := json.Unmarshal(data, &params)
 	if err != nil {
 		return err
@@ -252,7 +303,9 @@ func (r *GetCodeRequest) UnmarshalJSON(data []byte) error {
 		return errors.New("params must be set")
 	}
 
-	r.Address = params[0]
+	if err = json.Unmarshal(params[0], &r.Address); err != nil {
+		return err
+	}
 	if len(params) > 1 {
 		r.BlockNumber = params[1]
 	}
@@ -264,6 +317,10 @@ func (r *GetCodeRequest) UnmarshalJSON(data []byte) error {
 // a filter id
 type NewBlockFilterResponse string
 
+// ========== eth_newPendingTransactionFilter ============= //
+// a filter id
+type NewPendingTransactionFilterResponse string
+
 // ========== eth_uninstallFilter ============= //
 // the filter id
 type UninstallFilterRequest string
@@ -344,24 +401,24 @@ type (
 	  }
 	*/
 	GetBlockByNumberResponse struct {
-		Number           string   `json:"number"`
-		Hash             string   `json:"hash"`
-		ParentHash       string   `json:"parentHash"`
-		Nonce            string   `json:"nonce"`
-		Sha3Uncles       string   `json:"sha3Uncles"`
-		LogsBloom        string   `json:"logsBloom"`
-		TransactionsRoot string   `json:"transactionsRoot"`
-		StateRoot        string   `json:"stateRoot"`
-		Miner            string   `json:"miner"`
-		Difficulty       string   `json:"difficulty"`
-		TotalDifficulty  string   `json:"totalDifficulty"`
-		ExtraData        string   `json:"extraData"`
-		Size             string   `json:"size"`
-		GasLimit         string   `json:"gasLimit"`
-		GasUsed          string   `json:"gasUsed"`
-		Timestamp        string   `json:"timestamp"`
-		Transactions     []string `json:"transactions"`
-		Uncles           []string `json:"uncles"`
+		Number           string        `json:"number"`
+		Hash             string        `json:"hash"`
+		ParentHash       string        `json:"parentHash"`
+		Nonce            string        `json:"nonce"`
+		Sha3Uncles       string        `json:"sha3Uncles"`
+		LogsBloom        string        `json:"logsBloom"`
+		TransactionsRoot string        `json:"transactionsRoot"`
+		StateRoot        string        `json:"stateRoot"`
+		Miner            string        `json:"miner"`
+		Difficulty       string        `json:"difficulty"`
+		TotalDifficulty  string        `json:"totalDifficulty"`
+		ExtraData        string        `json:"extraData"`
+		Size             string        `json:"size"`
+		GasLimit         string        `json:"gasLimit"`
+		GasUsed          string        `json:"gasUsed"`
+		Timestamp        string        `json:"timestamp"`
+		Transactions     []interface{} `json:"transactions"` // hashes or *GetTransactionByHashResponse
+		Uncles           []string      `json:"uncles"`
 	}
 )
 
@@ -376,8 +433,10 @@ func (r *GetBlockByNumberRequest) UnmarshalJSON(data []byte) error {
 	}
 
 	var fullTx bool
-	if err := json.Unmarshal(params[1], &fullTx); err != nil {
-		return err
+	if len(params) > 1 {
+		if err := json.Unmarshal(params[1], &fullTx); err != nil {
+			return err
+		}
 	}
 
 	r.BlockNumber = params[0]
@@ -386,6 +445,36 @@ func (r *GetBlockByNumberRequest) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ========== eth_getBlockByHash ============= //
+
+type GetBlockByHashRequest struct {
+	BlockHash       string
+	FullTransaction bool
+}
+
+func (r *GetBlockByHashRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	if len(params) == 0 {
+		return errors.New("params must be set")
+	}
+
+	if err := json.Unmarshal(params[0], &r.BlockHash); err != nil {
+		return err
+	}
+
+	if len(params) > 1 {
+		if err := json.Unmarshal(params[1], &r.FullTransaction); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ========== eth_newFilter ============= //
 
 type NewFilterRequest struct {
@@ -432,3 +521,234 @@ func (r *GetBalanceRequest) UnmarshalJSON(data []byte) error {
 }
 
 type GetBalanceResponse string
+
+// ========== eth_getTransactionCount ============= //
+type (
+	GetTransactionCountRequest struct {
+		Address string
+		Tag     string // block number, "latest", "pending" or "earliest"
+	}
+
+	// the number of transactions sent from the address
+	GetTransactionCountResponse string
+)
+
+func (r *GetTransactionCountRequest) UnmarshalJSON(data []byte) error {
+	var params []string
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	if len(params) == 0 {
+		return errors.New("params must be set")
+	}
+
+	r.Address = params[0]
+	r.Tag = "latest"
+	if len(params) > 1 {
+		r.Tag = params[1]
+	}
+
+	return nil
+}
+
+// ========== eth_subscribe ============= //
+
+type (
+	EthSubscriptionRequest struct {
+		Type   string
+		Params *EthLogSubscriptionParameter
+	}
+
+	// only used by "logs" subscriptions
+	EthLogSubscriptionParameter struct {
+		Address json.RawMessage `json:"address"`
+		Topics  []interface{}   `json:"topics"`
+	}
+
+	// the subscription id
+	EthSubscriptionResponse string
+
+	// params of an eth_subscription notification
+	EthSubscription struct {
+		SubscriptionID string      `json:"subscription"`
+		Result         interface{} `json:"result"`
+	}
+)
+
+func (r *EthSubscriptionRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	if len(params) == 0 {
+		return errors.New("params must be set")
+	}
+
+	if err := json.Unmarshal(params[0], &r.Type); err != nil {
+		return err
+	}
+
+	if len(params) > 1 {
+		var param EthLogSubscriptionParameter
+		if err := json.Unmarshal(params[1], &param); err != nil {
+			return err
+		}
+		r.Params = &param
+	}
+
+	return nil
+}
+
+// ========== eth_unsubscribe ============= //
+
+type (
+	// the subscription id
+	EthUnsubscribeRequest string
+
+	// true if the subscription was successfully cancelled, otherwise false.
+	EthUnsubscribeResponse bool
+)
+
+func (r *EthUnsubscribeRequest) UnmarshalJSON(data []byte) error {
+	var params []string
+	err := json.Unmarshal(data, &params)
+	if err != nil {
+		return err
+	}
+
+	if len(params) == 0 {
+		return errors.New("params must be set")
+	}
+
+	*r = EthUnsubscribeRequest(params[0])
+
+	return nil
+}
+
+// ========== eth_feeHistory ============= //
+
+type (
+	FeeHistoryRequest struct {
+		BlockCount        json.RawMessage // QUANTITY, as a hex string or a number
+		NewestBlock       json.RawMessage // QUANTITY|TAG
+		RewardPercentiles []float64
+	}
+
+	FeeHistoryResponse struct {
+		OldestBlock   string     `json:"oldestBlock"`
+		BaseFeePerGas []string   `json:"baseFeePerGas"` // the base fee of each block and of the block after the newest one
+		GasUsedRatio  []float64  `json:"gasUsedRatio"`
+		Reward        [][]string `json:"reward,omitempty"` // the requested percentiles of the priority fees of each block
+	}
+)
+
+func (r *FeeHistoryRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+
+	if len(params) < 2 {
+		return errors.New("blockCount and newestBlock must be set")
+	}
+
+	r.BlockCount = params[0]
+	r.NewestBlock = params[1]
+	if len(params) > 2 {
+		if err := json.Unmarshal(params[2], &r.RewardPercentiles); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ========== evm_mine ============= //
+type (
+	// EVMMineRequest is [], [timestamp] or [{"blocks": n, "timestamp": timestamp}] like Ganache
+	EVMMineRequest struct {
+		Blocks    json.RawMessage // QUANTITY, 1 block if it is not set
+		Timestamp json.RawMessage // QUANTITY, the unix time in seconds of the blocks, not set keeps the clock
+	}
+
+	// always "0x0", like Ganache
+	EVMMineResponse string
+)
+
+func (r *EVMMineRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	if len(params) == 0 || string(params[0]) == "null" {
+		return nil
+	}
+
+	if params[0][0] != '{' {
+		r.Timestamp = params[0]
+		return nil
+	}
+
+	var options struct {
+		Blocks    json.RawMessage `json:"blocks"`
+		Timestamp json.RawMessage `json:"timestamp"`
+	}
+	if err := json.Unmarshal(params[0], &options); err != nil {
+		return err
+	}
+	r.Blocks, r.Timestamp = options.Blocks, options.Timestamp
+
+	return nil
+}
+
+// ========== evm_snapshot ============= //
+type (
+	// the id of the snapshot, as a hex string
+	EVMSnapshotResponse string
+)
+
+// ========== evm_revert ============= //
+type (
+	// the id of the snapshot, QUANTITY as a hex string or a number
+	EVMRevertRequest json.RawMessage
+
+	// false if there is no snapshot with the id
+	EVMRevertResponse bool
+)
+
+func (r *EVMRevertRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		return errors.New("snapshot id must be set")
+	}
+
+	*r = EVMRevertRequest(params[0])
+	return nil
+}
+
+// ========== evm_increaseTime ============= //
+type (
+	// seconds, QUANTITY as a hex string or a number
+	EVMIncreaseTimeRequest json.RawMessage
+
+	// the total seconds the clock was moved
+	EVMIncreaseTimeResponse int64
+)
+
+func (r *EVMIncreaseTimeRequest) UnmarshalJSON(data []byte) error {
+	var params []json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	if len(params) == 0 {
+		return errors.New("seconds must be set")
+	}
+
+	*r = EVMIncreaseTimeRequest(params[0])
+	return nil
+}
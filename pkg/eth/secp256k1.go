@@ -0,0 +1,137 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+// secp256k1 curve parameters, y² = x³ + 7 over Fp
+var (
+	secp256k1P, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secp256k1N, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secp256k1Gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	secp256k1Gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+var InvalidSignatureErr = errors.New("invalid signature")
+
+// curvePoint is an affine point, nil means the point at infinity
+type curvePoint struct {
+	x, y *big.Int
+}
+
+func (p *curvePoint) add(q *curvePoint) *curvePoint {
+	if p == nil {
+		return q
+	}
+	if q == nil {
+		return p
+	}
+
+	var lambda *big.Int
+	if p.x.Cmp(q.x) == 0 {
+		if p.y.Cmp(q.y) != 0 || p.y.Sign() == 0 {
+			return nil
+		}
+		// λ = 3x² / 2y
+		num := new(big.Int).Mul(p.x, p.x)
+		num.Mul(num, big.NewInt(3))
+		den := new(big.Int).Lsh(p.y, 1)
+		lambda = num.Mul(num, den.ModInverse(den, secp256k1P))
+	} else {
+		// λ = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(q.y, p.y)
+		den := new(big.Int).Sub(q.x, p.x)
+		den.Mod(den, secp256k1P)
+		lambda = num.Mul(num, den.ModInverse(den, secp256k1P))
+	}
+	lambda.Mod(lambda, secp256k1P)
+
+	x := new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, p.x)
+	x.Sub(x, q.x)
+	x.Mod(x, secp256k1P)
+
+	y := new(big.Int).Sub(p.x, x)
+	y.Mul(y, lambda)
+	y.Sub(y, p.y)
+	y.Mod(y, secp256k1P)
+
+	return &curvePoint{x: x, y: y}
+}
+
+func (p *curvePoint) mul(k *big.Int) *curvePoint {
+	var result *curvePoint
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.add(result)
+		if k.Bit(i) == 1 {
+			result = result.add(p)
+		}
+	}
+	return result
+}
+
+// ecrecover returns the uncompressed public key (64 bytes, without the 0x04 prefix)
+// which produced the signature of hash, recID is 0 or 1
+func ecrecover(hash []byte, r, s *big.Int, recID byte) ([]byte, error) {
+	if r.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Sign() <= 0 || s.Cmp(secp256k1N) >= 0 || recID > 1 {
+		return nil, InvalidSignatureErr
+	}
+
+	// R = (r, y) where the parity of y is given by recID
+	y2 := new(big.Int).Exp(r, big.NewInt(3), secp256k1P)
+	y2.Add(y2, big.NewInt(7))
+	y2.Mod(y2, secp256k1P)
+
+	// p ≡ 3 mod 4, so sqrt(a) = a^((p+1)/4)
+	exp := new(big.Int).Add(secp256k1P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(y2, exp, secp256k1P)
+	if new(big.Int).Exp(y, big.NewInt(2), secp256k1P).Cmp(y2) != 0 {
+		return nil, InvalidSignatureErr
+	}
+	if y.Bit(0) != uint(recID) {
+		y.Sub(secp256k1P, y)
+	}
+	R := &curvePoint{x: r, y: y}
+	G := &curvePoint{x: secp256k1Gx, y: secp256k1Gy}
+
+	// Q = r⁻¹(sR - eG)
+	e := new(big.Int).SetBytes(hash)
+	e.Mod(e, secp256k1N)
+	rInv := new(big.Int).ModInverse(r, secp256k1N)
+
+	u1 := new(big.Int).Neg(e)
+	u1.Mul(u1, rInv)
+	u1.Mod(u1, secp256k1N)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, secp256k1N)
+
+	Q := G.mul(u1).add(R.mul(u2))
+	if Q == nil {
+		return nil, InvalidSignatureErr
+	}
+
+	pub := make([]byte, 64)
+	xBytes, yBytes := Q.x.Bytes(), Q.y.Bytes()
+	copy(pub[32-len(xBytes):32], xBytes)
+	copy(pub[64-len(yBytes):], yBytes)
+	return pub, nil
+}
+
+// Keccak256 is the hash function Ethereum calls sha3
+func Keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// PubkeyToAddress returns the 20 bytes address of an uncompressed public key
+func PubkeyToAddress(pub []byte) []byte {
+	return Keccak256(pub)[12:]
+}
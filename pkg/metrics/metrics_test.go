@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	r := NewRegistry()
+
+	requests := r.NewCounterVec("requests_total", "Requests", "method")
+	requests.Inc("eth_call")
+	requests.Add(2, `say "hi"`)
+
+	filters := r.NewGauge("filters_active", "Filters")
+	filters.Inc()
+	filters.Inc()
+	filters.Dec()
+
+	latency := r.NewHistogramVec("latency_seconds", "Latency", []float64{1, 0.1}, "method")
+	latency.Observe(0.05, "eth_call")
+	latency.Observe(0.5, "eth_call")
+	latency.Observe(5, "eth_call")
+
+	want := `# HELP requests_total Requests
+# TYPE requests_total counter
+requests_total{method="eth_call"} 1
+requests_total{method="say \"hi\""} 2
+# HELP filters_active Filters
+# TYPE filters_active gauge
+filters_active 1
+# HELP latency_seconds Latency
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="eth_call",le="0.1"} 1
+latency_seconds_bucket{method="eth_call",le="1"} 2
+latency_seconds_bucket{method="eth_call",le="+Inf"} 3
+latency_seconds_sum{method="eth_call"} 5.55
+latency_seconds_count{method="eth_call"} 3
+`
+
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,126 @@
+package qtum
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/dcb9/janus/pkg/utils"
+)
+
+// Version bytes of P2PKH addresses, see: https://github.com/qtumproject/qtum/blob/master/src/chainparams.cpp
+const (
+	PubKeyHashAddrIDMain = 0x3a // Q...
+	PubKeyHashAddrIDTest = 0x78 // q..., regtest uses the same version as testnet
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// InvalidAddressErr is the error of qtumd for addresses it cannot decode, so it is handled like a failed gethexaddress call
+var InvalidAddressErr = &JSONRPCError{Code: ErrCodeInvalidAddressOrKey, Message: "Invalid Qtum address"}
+
+// PubKeyHashAddrID returns the version byte of P2PKH addresses on chain
+func PubKeyHashAddrID(chain string) byte {
+	if chain == ChainMain {
+		return PubKeyHashAddrIDMain
+	}
+	return PubKeyHashAddrIDTest
+}
+
+// HexToBase58Address encodes a hex public key hash as a P2PKH address of the chain, like fromhexaddress
+func (c *Qtum) HexToBase58Address(hexAddress string) (string, error) {
+	pubKeyHash, err := hex.DecodeString(utils.RemoveHexPrefix(hexAddress))
+	if err != nil || len(pubKeyHash) != 20 {
+		return "", InvalidAddressErr
+	}
+
+	return Base58CheckEncode(PubKeyHashAddrID(c.chain), pubKeyHash), nil
+}
+
+// Base58ToHexAddress decodes a P2PKH address of the chain to the hex public key hash without 0x, like gethexaddress
+func (c *Qtum) Base58ToHexAddress(base58Address string) (string, error) {
+	version, pubKeyHash, err := Base58CheckDecode(base58Address)
+	if err != nil || version != PubKeyHashAddrID(c.chain) || len(pubKeyHash) != 20 {
+		return "", InvalidAddressErr
+	}
+
+	return hex.EncodeToString(pubKeyHash), nil
+}
+
+// Base58CheckEncode encodes version + payload + checksum in base58
+func Base58CheckEncode(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	data = append(data, base58Checksum(data)...)
+
+	return base58Encode(data)
+}
+
+// Base58CheckDecode returns the version and payload of a base58check string
+func Base58CheckDecode(s string) (byte, []byte, error) {
+	data, err := base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, InvalidAddressErr
+	}
+
+	checksum := data[len(data)-4:]
+	data = data[:len(data)-4]
+	if !bytes.Equal(checksum, base58Checksum(data)) {
+		return 0, nil, InvalidAddressErr
+	}
+
+	return data[0], data[1:], nil
+}
+
+func base58Checksum(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	// leading zero bytes are encoded as '1'
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		i := bytes.IndexRune([]byte(base58Alphabet), r)
+		if i < 0 {
+			return nil, InvalidAddressErr
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
@@ -0,0 +1,51 @@
+package qtum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBase58Address(t *testing.T) {
+	// the prefunded accounts of the playground
+	cases := []struct {
+		base58 string
+		hex    string
+	}{
+		{"qUbxboqjBRp96j3La8D1RYkyqx5uQbJPoW", "7926223070547d2d15b2ef5e7383e541c338ffe9"},
+		{"qLn9vqbr2Gx3TsVR9QyTVB5mrMoh4x43Uf", "2352be3db3177f0a07efbe6da5857615b8c9901d"},
+	}
+
+	regtest := &Qtum{chain: ChainRegTest}
+	for _, c := range cases {
+		got, err := regtest.Base58ToHexAddress(c.base58)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.hex {
+			t.Errorf("in: %s, want: %s, got: %s", c.base58, c.hex, got)
+		}
+
+		got, err = regtest.HexToBase58Address("0x" + c.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.base58 {
+			t.Errorf("in: %s, want: %s, got: %s", c.hex, c.base58, got)
+		}
+	}
+
+	main := &Qtum{chain: ChainMain}
+	mainAddress, err := main.HexToBase58Address(cases[0].hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(mainAddress, "Q") {
+		t.Errorf("in: %s, want: Q..., got: %s", cases[0].hex, mainAddress)
+	}
+	if _, err := main.Base58ToHexAddress(cases[0].base58); err != InvalidAddressErr {
+		t.Errorf("in: %s, want: %v, got: %v", cases[0].base58, InvalidAddressErr, err)
+	}
+	if _, err := regtest.Base58ToHexAddress("qUbxboqjBRp96j3La8D1RYkyqx5uQbJPoX"); err != InvalidAddressErr {
+		t.Errorf("bad checksum, want: %v, got: %v", InvalidAddressErr, err)
+	}
+}
@@ -0,0 +1,122 @@
+package qtum
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// amountDecimals is the number of decimals of QTUM, 1 QTUM is 1e8 satoshis
+const amountDecimals = 8
+
+var satoshisPerQtum = big.NewInt(1e8)
+
+// Amount is an exact amount of satoshis, qtumd encodes amounts as decimal QTUM, e.g. 0.00000040
+type Amount struct {
+	*big.Int
+}
+
+func NewAmount(satoshis int64) Amount {
+	return Amount{big.NewInt(satoshis)}
+}
+
+// Satoshis returns the amount in satoshis, 0 if it is not set
+func (a Amount) Satoshis() *big.Int {
+	if a.Int == nil {
+		return new(big.Int)
+	}
+	return a.Int
+}
+
+func (a Amount) String() string {
+	return FormatAmount(a.Satoshis())
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(FormatAmount(a.Satoshis())), nil
+}
+
+// UnmarshalJSON accepts amounts encoded as numbers or strings, like qtumd does
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	satoshis, err := ParseAmount(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	a.Int = satoshis
+	return nil
+}
+
+// ParseAmount parses a decimal amount of QTUM, e.g. "0.1" or "-2.00000001", into satoshis without rounding
+func ParseAmount(s string) (*big.Int, error) {
+	sign := ""
+	digits := s
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	whole, frac := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, frac = digits[:i], digits[i+1:]
+	}
+	if whole == "" && frac == "" {
+		return nil, errors.Errorf("invalid amount %q", s)
+	}
+	if len(frac) > amountDecimals {
+		return nil, errors.Errorf("invalid amount %q, QTUM has %d decimals", s, amountDecimals)
+	}
+
+	digits = whole + frac + strings.Repeat("0", amountDecimals-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, errors.Errorf("invalid amount %q", s)
+		}
+	}
+
+	satoshis, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid amount %q", s)
+	}
+	return satoshis, nil
+}
+
+// FormatAmount formats satoshis as decimal QTUM with all 8 decimals, e.g. "0.00000040"
+func FormatAmount(satoshis *big.Int) string {
+	sign := ""
+	if satoshis.Sign() < 0 {
+		sign = "-"
+	}
+
+	qtums, rest := new(big.Int).QuoRem(new(big.Int).Abs(satoshis), satoshisPerQtum, new(big.Int))
+	return fmt.Sprintf("%s%s.%0*d", sign, qtums, amountDecimals, rest.Int64())
+}
+
+// WeiToSatoshis converts an amount of wei of Ethereum clients into satoshis, it fails rather than rounding
+// if the amount is not a whole number of satoshis
+func (c *Qtum) WeiToSatoshis(wei *big.Int) (*big.Int, error) {
+	if wei.Sign() < 0 {
+		return nil, errors.Errorf("negative amount %s wei", wei)
+	}
+
+	satoshis, rest := new(big.Int).QuoRem(wei, c.weiPerSatoshi, new(big.Int))
+	if rest.Sign() != 0 {
+		return nil, errors.Errorf("%s wei is not a whole number of satoshis, 1 satoshi is %s wei", wei, c.weiPerSatoshi)
+	}
+	return satoshis, nil
+}
+
+// SatoshisToWei converts an amount of satoshis into wei of Ethereum clients
+func (c *Qtum) SatoshisToWei(satoshis *big.Int) *big.Int {
+	return new(big.Int).Mul(satoshis, c.weiPerSatoshi)
+}
+
+// WeiPerSatoshi returns how many wei of Ethereum clients are a satoshi
+func (c *Qtum) WeiPerSatoshi() *big.Int {
+	return new(big.Int).Set(c.weiPerSatoshi)
+}
@@ -0,0 +1,148 @@
+package qtum
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string // satoshis, empty if invalid
+	}{
+		{"0", "0"},
+		{"0.00000001", "1"},
+		{"0.0000004", "40"},
+		{"0.1", "10000000"},
+		{".5", "50000000"},
+		{"1.", "100000000"},
+		{"1", "100000000"},
+		{"-0.00000001", "-1"},
+		{"-2.5", "-250000000"},
+		{"107822406.25", "10782240625000000"},
+		// more than float64 can represent exactly
+		{"92233720368.54775809", "9223372036854775809"},
+		{"123456789012345678901234567890.12345678", "12345678901234567890123456789012345678"},
+		{"", ""},
+		{"-", ""},
+		{".", ""},
+		{"0.000000001", ""},
+		{"1e-8", ""},
+		{"1.2.3", ""},
+		{"--1", ""},
+		{"0x10", ""},
+	}
+
+	for _, c := range cases {
+		got, err := ParseAmount(c.in)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("in: %q, want: error, got: %s", c.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("in: %q, want: %s, got: %s", c.in, c.want, err)
+			continue
+		}
+		if got.String() != c.want {
+			t.Errorf("in: %q, want: %s, got: %s", c.in, c.want, got)
+		}
+	}
+}
+
+func TestAmountRoundTrip(t *testing.T) {
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(-1), big.NewInt(99999999), big.NewInt(100000000), big.NewInt(100000001)}
+	// every power of 10 and its neighbours, up to far more than the supply of QTUM
+	for p := new(big.Int).SetInt64(10); p.BitLen() < 128; p = new(big.Int).Mul(p, big.NewInt(10)) {
+		for _, d := range []int64{-1, 0, 1} {
+			v := new(big.Int).Add(p, big.NewInt(d))
+			values = append(values, v, new(big.Int).Neg(v))
+		}
+	}
+
+	for _, v := range values {
+		data, err := json.Marshal(Amount{v})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got Amount
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("in: %s, json: %s, got: %s", v, data, err)
+			continue
+		}
+		if got.Cmp(v) != 0 {
+			t.Errorf("in: %s, json: %s, got: %s", v, data, got.Satoshis())
+		}
+
+		// qtumd accepts amounts as strings too
+		if err := json.Unmarshal([]byte(`"`+string(data)+`"`), &got); err != nil || got.Cmp(v) != 0 {
+			t.Errorf("in: %q, want: %s, got: %s, err: %v", data, v, got.Satoshis(), err)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0.00000000"},
+		{40, "0.00000040"},
+		{-1, "-0.00000001"},
+		{123456789, "1.23456789"},
+		{-250000000, "-2.50000000"},
+	}
+
+	for _, c := range cases {
+		if got := FormatAmount(big.NewInt(c.in)); got != c.want {
+			t.Errorf("in: %d, want: %s, got: %s", c.in, c.want, got)
+		}
+	}
+
+	if got, _ := json.Marshal(Amount{}); string(got) != "0.00000000" {
+		t.Errorf("in: unset amount, want: 0.00000000, got: %s", got)
+	}
+}
+
+func TestWeiSatoshis(t *testing.T) {
+	e10 := new(big.Int).Exp(big.NewInt(10), big.NewInt(10), nil)
+
+	for _, weiPerSatoshi := range []*big.Int{big.NewInt(1), big.NewInt(7), e10} {
+		q, err := New(&Client{}, ChainRegTest, SetWeiPerSatoshi(weiPerSatoshi))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, s := range []string{"0", "1", "40", "100000000", "10782240625000000", "12345678901234567890123456789"} {
+			satoshis, _ := new(big.Int).SetString(s, 10)
+
+			wei := q.SatoshisToWei(satoshis)
+			if want := new(big.Int).Mul(satoshis, weiPerSatoshi); wei.Cmp(want) != 0 {
+				t.Errorf("in: %s satoshis, %s wei per satoshi, want: %s wei, got: %s", s, weiPerSatoshi, want, wei)
+			}
+
+			got, err := q.WeiToSatoshis(wei)
+			if err != nil || got.Cmp(satoshis) != 0 {
+				t.Errorf("in: %s wei, %s wei per satoshi, want: %s satoshis, got: %s, err: %v", wei, weiPerSatoshi, s, got, err)
+			}
+
+			// a fraction of a satoshi cannot be sent
+			if weiPerSatoshi.Cmp(big.NewInt(1)) > 0 {
+				if _, err := q.WeiToSatoshis(new(big.Int).Add(wei, big.NewInt(1))); err == nil {
+					t.Errorf("in: %s wei, %s wei per satoshi, want: error, got: nil", wei.String()+"+1", weiPerSatoshi)
+				}
+			}
+		}
+
+		if _, err := q.WeiToSatoshis(big.NewInt(-1)); err == nil {
+			t.Errorf("in: -1 wei, want: error, got: nil")
+		}
+	}
+
+	if _, err := New(&Client{}, ChainRegTest, SetWeiPerSatoshi(big.NewInt(0))); err == nil {
+		t.Errorf("in: 0 wei per satoshi, want: error, got: nil")
+	}
+}
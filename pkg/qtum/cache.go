@@ -0,0 +1,208 @@
+package qtum
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+
+	"github.com/dcb9/janus/pkg/metrics"
+	"github.com/go-kit/kit/log/level"
+)
+
+var (
+	cacheHitsTotal   = metrics.NewCounterVec("janus_qtum_cache_hits_total", "qtumd RPC calls answered by the response cache, by method", "method")
+	cacheMissesTotal = metrics.NewCounterVec("janus_qtum_cache_misses_total", "qtumd RPC calls which could be cached but were not, by method", "method")
+)
+
+// responseCache is an LRU cache of the results of qtumd calls about data which is deep enough in the chain
+// to never change. The confirmations of cached blocks and transactions are not updated.
+type responseCache struct {
+	// the maximum number of results, 0 disables the cache
+	size int
+	// how many confirmations a result needs to be cached
+	confirmations int64
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is the most recently used
+	// height of the best block seen, 0 if it is unknown
+	tip    int64
+	hits   uint64
+	misses uint64
+}
+
+type cacheEntry struct {
+	key    string
+	result json.RawMessage
+}
+
+// CacheStats are the statistics of the response cache
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+	Size    int
+}
+
+func newResponseCache(size int, confirmations int64) *responseCache {
+	return &responseCache{
+		size:          size,
+		confirmations: confirmations,
+		entries:       make(map[string]*list.Element),
+		lru:           list.New(),
+	}
+}
+
+func (c *responseCache) get(key string) (json.RawMessage, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.lru.MoveToFront(e)
+	return e.Value.(*cacheEntry).result, true
+}
+
+func (c *responseCache) add(key string, result json.RawMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, result: result})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *responseCache) setTip(height int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tip = height
+}
+
+func (c *responseCache) getTip() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.tip
+}
+
+// confirmationsFunc returns how many confirmations the result of a call has
+type confirmationsFunc func(c *responseCache, result json.RawMessage) int64
+
+// immutable is the confirmationsFunc of calls whose results only depend on their params
+func immutable(*responseCache, json.RawMessage) int64 {
+	return math.MaxInt64
+}
+
+// confirmationsField reads the confirmations of a block or a transaction
+func confirmationsField(c *responseCache, result json.RawMessage) int64 {
+	var v struct {
+		Confirmations int64 `json:"confirmations"`
+		Height        int64 `json:"height"`
+	}
+	if err := json.Unmarshal(result, &v); err != nil {
+		return 0
+	}
+	if v.Height > 0 && v.Confirmations > 0 {
+		c.setTip(v.Height + v.Confirmations - 1)
+	}
+	return v.Confirmations
+}
+
+// heightConfirmations returns the confirmationsFunc of calls about the block at height
+func heightConfirmations(height int64) confirmationsFunc {
+	return func(c *responseCache, _ json.RawMessage) int64 {
+		tip := c.getTip()
+		if tip == 0 {
+			return 0
+		}
+		return tip - height + 1
+	}
+}
+
+// receiptsConfirmations returns the confirmations of the least confirmed receipt, pending transactions have no receipts
+func receiptsConfirmations(c *responseCache, result json.RawMessage) int64 {
+	var receipts []struct {
+		BlockNumber int64 `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(result, &receipts); err != nil || len(receipts) == 0 {
+		return 0
+	}
+
+	tip := c.getTip()
+	if tip == 0 {
+		return 0
+	}
+	confirmations := int64(math.MaxInt64)
+	for _, r := range receipts {
+		if n := tip - r.BlockNumber + 1; n < confirmations {
+			confirmations = n
+		}
+	}
+	return confirmations
+}
+
+// cachedRequest is Request for calls whose results never change once they have enough confirmations
+func (c *Client) cachedRequest(ctx context.Context, method string, params interface{}, result interface{}, confirmations confirmationsFunc) error {
+	if c.cache.size <= 0 {
+		return c.Request(ctx, method, params, result)
+	}
+
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	key := method + string(paramsJSON)
+
+	raw, ok := c.cache.get(key)
+	if ok {
+		cacheHitsTotal.Inc(method)
+		level.Debug(c.logger).Log("method", method, "cache", "hit")
+	} else {
+		cacheMissesTotal.Inc(method)
+		if err := c.Request(ctx, method, params, &raw); err != nil {
+			return err
+		}
+		if confirmations(c.cache, raw) >= c.cache.confirmations {
+			c.cache.add(key, raw)
+		}
+	}
+
+	return json.Unmarshal(raw, result)
+}
+
+// PurgeCache empties the response cache, the cached results are wrong after blocks deeper than the cached
+// confirmations are invalidated, e.g. by evm_revert on regtest
+func (c *Client) PurgeCache() {
+	c.cache.mutex.Lock()
+	defer c.cache.mutex.Unlock()
+
+	c.cache.entries = make(map[string]*list.Element)
+	c.cache.lru.Init()
+	c.cache.tip = 0
+}
+
+// CacheStats returns the statistics of the response cache
+func (c *Client) CacheStats() CacheStats {
+	c.cache.mutex.Lock()
+	defer c.cache.mutex.Unlock()
+
+	return CacheStats{
+		Hits:    c.cache.hits,
+		Misses:  c.cache.misses,
+		Entries: c.cache.lru.Len(),
+		Size:    c.cache.size,
+	}
+}
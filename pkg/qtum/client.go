@@ -2,6 +2,7 @@ package qtum
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,20 +10,46 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/dcb9/janus/pkg/metrics"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 )
 
+var (
+	qtumRequestDuration    = metrics.NewHistogramVec("janus_qtum_request_duration_seconds", "Latency of qtumd RPC calls by method, including retries", metrics.DefaultBuckets, "method")
+	qtumRequestErrorsTotal = metrics.NewCounterVec("janus_qtum_request_errors_total", "qtumd RPC calls which failed by method and error code, the code of connection errors is \"transport\"", "method", "code")
+)
+
 type Client struct {
+	// URL of the wallet node, wallet methods are only sent to it
 	URL  string
 	doer doer
 
+	// the wallet node is the first upstream, read methods are spread over all healthy upstreams
+	upstreams           []*upstream
+	nextUpstream        uint64
+	healthCheckInterval time.Duration
+	// how many blocks an upstream can be behind the best upstream to be healthy
+	maxLag   int64
+	stop     chan struct{}
+	stopOnce sync.Once
+
 	logger log.Logger
 	debug  bool
 
+	// timeout of a single call to qtumd, 0 means no timeout
+	timeout time.Duration
+	// how many times a read method is retried after a connection error, the delay doubles after each retry
+	retries      int
+	retryBackoff time.Duration
+
+	cache *responseCache
+
 	id      *big.Int
 	idStep  *big.Int
 	idMutex sync.Mutex
@@ -35,10 +62,22 @@ func NewClient(rpcURL string, opts ...func(*Client) error) (*Client, error) {
 	}
 
 	c := &Client{
-		doer:   http.DefaultClient,
-		URL:    rpcURL,
-		logger: log.NewNopLogger(),
-		debug:  false,
+		doer: http.DefaultClient,
+		URL:  rpcURL,
+
+		upstreams:           []*upstream{{url: rpcURL, healthy: true}},
+		healthCheckInterval: 5 * time.Second,
+		maxLag:              2,
+		stop:                make(chan struct{}),
+		logger:              log.NewNopLogger(),
+		debug:               false,
+
+		timeout:      30 * time.Second,
+		retries:      2,
+		retryBackoff: 100 * time.Millisecond,
+
+		cache: newResponseCache(10000, 6),
+
 		id:     big.NewInt(0),
 		idStep: big.NewInt(1),
 	}
@@ -49,16 +88,20 @@ func NewClient(rpcURL string, opts ...func(*Client) error) (*Client, error) {
 		}
 	}
 
+	if len(c.upstreams) > 1 {
+		go c.healthCheckLoop()
+	}
+
 	return c, nil
 }
 
-func (c *Client) Request(method string, params interface{}, result interface{}) error {
+func (c *Client) Request(ctx context.Context, method string, params interface{}, result interface{}) error {
 	r, err := c.NewRPCRequest(method, params)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.Do(r)
+	resp, err := c.Do(ctx, r)
 	if err != nil {
 		return err
 	}
@@ -70,7 +113,7 @@ func (c *Client) Request(method string, params interface{}, result interface{})
 	return nil
 }
 
-func (c *Client) Do(req *JSONRPCRequest) (*SuccessJSONRPCResult, error) {
+func (c *Client) Do(ctx context.Context, req *JSONRPCRequest) (*SuccessJSONRPCResult, error) {
 	reqBody, err := json.MarshalIndent(req, "", "  ")
 	if err != nil {
 		return nil, err
@@ -85,8 +128,11 @@ func (c *Client) Do(req *JSONRPCRequest) (*SuccessJSONRPCResult, error) {
 		// l.Log("reqBody", reqBody)
 	}
 
-	respBody, err := c.do(bytes.NewReader(reqBody))
+	start := time.Now()
+	respBody, err := c.doWithRetries(ctx, req.Method, reqBody)
+	qtumRequestDuration.Observe(time.Since(start).Seconds(), req.Method)
 	if err != nil {
+		qtumRequestErrorsTotal.Inc(req.Method, "transport")
 		return nil, errors.Wrap(err, "Client#do")
 	}
 
@@ -108,6 +154,9 @@ func (c *Client) Do(req *JSONRPCRequest) (*SuccessJSONRPCResult, error) {
 	}
 
 	res, err := responseBodyToResult(respBody)
+	if rpcErr, ok := err.(*JSONRPCError); ok {
+		qtumRequestErrorsTotal.Inc(req.Method, strconv.Itoa(rpcErr.Code))
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "responseBodyToResult")
 	}
@@ -123,18 +172,67 @@ func (c *Client) NewRPCRequest(method string, params interface{}) (*JSONRPCReque
 
 	c.idMutex.Lock()
 	c.id = c.id.Add(c.id, c.idStep)
+	id := c.id.String()
 	c.idMutex.Unlock()
 
 	return &JSONRPCRequest{
 		JSONRPC: RPCVersion,
-		ID:      json.RawMessage(`"` + c.id.String() + `"`),
+		ID:      json.RawMessage(`"` + id + `"`),
 		Method:  method,
 		Params:  paramsJSON,
 	}, nil
 }
 
-func (c *Client) do(body io.Reader) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodPost, c.URL, body)
+// doWithRetries retries read methods after connection errors, errors returned by qtumd are never retried.
+// The upstream of a connection error is taken out of rotation, so read methods are retried on another upstream,
+// but not the upstream of a call which timed out, a slow call, e.g. searchlogs, doesn't mean the node is down.
+func (c *Client) doWithRetries(ctx context.Context, method string, body []byte) ([]byte, error) {
+	retries := 0
+	if isReadMethod(method) {
+		retries = c.retries
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		u := c.upstreamFor(ctx, method)
+		respBody, err := c.do(ctx, u.url, bytes.NewReader(body))
+		if _, timeout := err.(*timeoutError); err != nil && !timeout && ctx.Err() == nil {
+			c.markDown(u, err)
+		}
+		if err == nil || attempt >= retries || ctx.Err() != nil {
+			return respBody, err
+		}
+
+		level.Warn(c.logger).Log("msg", "retry qtum RPC", "method", method, "attempt", attempt+1, "err", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// timeoutError is the error of a call which took longer than the timeout of the client
+type timeoutError struct {
+	error
+}
+
+func (c *Client) do(parent context.Context, upstreamURL string, body io.Reader) (respBody []byte, err error) {
+	ctx := parent
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, c.timeout)
+		defer cancel()
+	}
+	defer func() {
+		if err != nil && ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+			err = &timeoutError{err}
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +269,96 @@ func SetDebug(debug bool) func(*Client) error {
 	}
 }
 
+// SetTimeout sets the timeout of a single call to qtumd, 0 disables it
+func SetTimeout(timeout time.Duration) func(*Client) error {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return errors.New("timeout cannot be negative")
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// SetRetries sets how many times read methods are retried after connection errors, 0 disables retries
+func SetRetries(retries int) func(*Client) error {
+	return func(c *Client) error {
+		if retries < 0 {
+			return errors.New("retries cannot be negative")
+		}
+		c.retries = retries
+		return nil
+	}
+}
+
+// SetRetryBackoff sets the delay before the first retry, it doubles after each retry
+func SetRetryBackoff(backoff time.Duration) func(*Client) error {
+	return func(c *Client) error {
+		if backoff < 0 {
+			return errors.New("retry backoff cannot be negative")
+		}
+		c.retryBackoff = backoff
+		return nil
+	}
+}
+
+// SetCacheSize sets how many results of calls about confirmed data are cached, 0 disables the cache
+func SetCacheSize(size int) func(*Client) error {
+	return func(c *Client) error {
+		if size < 0 {
+			return errors.New("cache size cannot be negative")
+		}
+		c.cache.size = size
+		return nil
+	}
+}
+
+// SetCacheConfirmations sets how many confirmations blocks and transactions need to be cached
+func SetCacheConfirmations(confirmations int64) func(*Client) error {
+	return func(c *Client) error {
+		if confirmations < 1 {
+			return errors.New("cache confirmations must be positive")
+		}
+		c.cache.confirmations = confirmations
+		return nil
+	}
+}
+
+// SetUpstreams adds qtumd nodes read methods are spread over, besides the wallet node
+func SetUpstreams(urls ...string) func(*Client) error {
+	return func(c *Client) error {
+		for _, u := range urls {
+			if err := checkRPCURL(u); err != nil {
+				return err
+			}
+			c.upstreams = append(c.upstreams, &upstream{url: u, healthy: true})
+		}
+		return nil
+	}
+}
+
+// SetHealthCheckInterval sets how often the upstreams are checked
+func SetHealthCheckInterval(interval time.Duration) func(*Client) error {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return errors.New("health check interval must be positive")
+		}
+		c.healthCheckInterval = interval
+		return nil
+	}
+}
+
+// SetMaxLag sets how many blocks an upstream can be behind the best upstream to get read methods
+func SetMaxLag(blocks int64) func(*Client) error {
+	return func(c *Client) error {
+		if blocks < 0 {
+			return errors.New("max lag cannot be negative")
+		}
+		c.maxLag = blocks
+		return nil
+	}
+}
+
 func SetLogger(l log.Logger) func(*Client) error {
 	return func(c *Client) error {
 		c.logger = log.WithPrefix(l, "component", "qtum.Client")
@@ -10,8 +10,6 @@ const (
 )
 
 const (
-	MethodGetHexAddress         = "gethexaddress"
-	MethodFromHexAddress        = "fromhexaddress"
 	MethodSendToContract        = "sendtocontract"
 	MethodGetTransactionReceipt = "gettransactionreceipt"
 	MethodGetTransaction        = "gettransaction"
@@ -30,6 +28,22 @@ const (
 	MethodGetAccountInfo        = "getaccountinfo"
 	MethodGenerate              = "generate"
 	MethodListUnspent           = "listunspent"
+	MethodGetRawMempool         = "getrawmempool"
+	MethodSignRawTransaction    = "signrawtransaction"
+	MethodSendRawTransaction    = "sendrawtransaction"
+	MethodGetDGPInfo            = "getdgpinfo"
+	MethodGetRawTransaction     = "getrawtransaction"
+	MethodGetAddressDeltas      = "getaddressdeltas"
+	MethodGetAddressMempool     = "getaddressmempool"
+	MethodGetWalletInfo         = "getwalletinfo"
+	MethodGetNetworkInfo        = "getnetworkinfo"
+	MethodGetConnectionCount    = "getconnectioncount"
+	MethodGetMiningInfo         = "getmininginfo"
+	MethodGetStakingInfo        = "getstakinginfo"
+	MethodInvalidateBlock       = "invalidateblock"
+	MethodReconsiderBlock       = "reconsiderblock"
+	MethodSetMockTime           = "setmocktime"
+	MethodPrioritiseTransaction = "prioritisetransaction"
 )
 
 type JSONRPCRequest struct {
@@ -46,6 +60,69 @@ type JSONRPCResult struct {
 	ID        json.RawMessage `json:"id"`
 }
 
+// readMethods don't change the state of qtumd or its wallet, so they can be retried safely
+var readMethods = map[string]bool{
+	MethodGetTransactionReceipt: true,
+	MethodGetTransaction:        true,
+	MethodCallContract:          true,
+	MethodDecodeRawTransaction:  true,
+	MethodGetBlockCount:         true,
+	MethodGetBlockChainInfo:     true,
+	MethodSearchLogs:            true,
+	MethodGetBlockHash:          true,
+	MethodGetBlockHeader:        true,
+	MethodGetBlock:              true,
+	MethodGetAddressesByAccount: true,
+	MethodGetAccountInfo:        true,
+	MethodListUnspent:           true,
+	MethodGetRawMempool:         true,
+	MethodGetDGPInfo:            true,
+	MethodGetRawTransaction:     true,
+	MethodGetAddressDeltas:      true,
+	MethodGetAddressMempool:     true,
+	MethodGetWalletInfo:         true,
+	MethodGetNetworkInfo:        true,
+	MethodGetConnectionCount:    true,
+	MethodGetMiningInfo:         true,
+	MethodGetStakingInfo:        true,
+}
+
+func isReadMethod(method string) bool {
+	return readMethods[method]
+}
+
+// walletMethods use the wallet of qtumd or change the chain of its node, so they are pinned to the wallet node
+var walletMethods = map[string]bool{
+	MethodSendToContract:        true,
+	MethodCreateContract:        true,
+	MethodSendToAddress:         true,
+	MethodGetTransaction:        true,
+	MethodGetAddressesByAccount: true,
+	MethodGenerate:              true,
+	MethodListUnspent:           true,
+	MethodSignRawTransaction:    true,
+	MethodSendRawTransaction:    true,
+	MethodGetWalletInfo:         true,
+	MethodGetStakingInfo:        true,
+	MethodInvalidateBlock:       true,
+	MethodReconsiderBlock:       true,
+	MethodSetMockTime:           true,
+	MethodPrioritiseTransaction: true,
+}
+
+func isWalletMethod(method string) bool {
+	return walletMethods[method]
+}
+
+// Error codes of qtumd caused by the parameters of the request,
+// see: https://github.com/qtumproject/qtum/blob/master/src/rpc/protocol.h
+const (
+	ErrCodeTypeError            = -3
+	ErrCodeInvalidAddressOrKey  = -5
+	ErrCodeInvalidParameter     = -8
+	ErrCodeDeserializationError = -22
+)
+
 type JSONRPCError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
@@ -1,40 +1,34 @@
 package qtum
 
 import (
+	"context"
+	"encoding/json"
 	"math/big"
-
-	"github.com/dcb9/janus/pkg/utils"
 )
 
 type Method struct {
 	*Client
 }
 
-func (m *Method) Base58AddressToHex(addr string) (string, error) {
-	var response GetHexAddressResponse
-	err := m.Request(MethodGetHexAddress, GetHexAddressRequest(addr), &response)
+func (m *Method) GetTransactionReceipt(ctx context.Context, txHash string) (*GetTransactionReceiptResponse, error) {
+	var resp *GetTransactionReceiptResponse
+	err := m.cachedRequest(ctx, MethodGetTransactionReceipt, GetTransactionReceiptRequest(txHash), &resp, receiptsConfirmations)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(response), nil
+	return resp, nil
 }
 
-func (m *Method) FromHexAddress(addr string) (string, error) {
-	addr = utils.RemoveHexPrefix(addr)
-
-	var response FromHexAddressResponse
-	err := m.Request(MethodFromHexAddress, FromHexAddressRequest(addr), &response)
-	if err != nil {
-		return "", err
-	}
-
-	return string(response), nil
+// GetTransactionReceipts returns a receipt for each OP_CALL/OP_CREATE output of the transaction
+func (m *Method) GetTransactionReceipts(ctx context.Context, txHash string) (receipts []TransactionReceiptStruct, err error) {
+	err = m.cachedRequest(ctx, MethodGetTransactionReceipt, GetTransactionReceiptRequest(txHash), &receipts, receiptsConfirmations)
+	return
 }
 
-func (m *Method) GetTransactionReceipt(txHash string) (*GetTransactionReceiptResponse, error) {
-	var resp *GetTransactionReceiptResponse
-	err := m.Request(MethodGetTransactionReceipt, GetTransactionReceiptRequest(txHash), &resp)
+func (m *Method) DecodeRawTransaction(ctx context.Context, hex string) (*DecodedRawTransactionResponse, error) {
+	var resp *DecodedRawTransactionResponse
+	err := m.cachedRequest(ctx, MethodDecodeRawTransaction, DecodeRawTransactionRequest(hex), &resp, immutable)
 	if err != nil {
 		return nil, err
 	}
@@ -42,78 +36,194 @@ func (m *Method) GetTransactionReceipt(txHash string) (*GetTransactionReceiptRes
 	return resp, nil
 }
 
-func (m *Method) DecodeRawTransaction(hex string) (*DecodedRawTransactionResponse, error) {
-	var resp *DecodedRawTransactionResponse
-	err := m.Request(MethodDecodeRawTransaction, DecodeRawTransactionRequest(hex), &resp)
-	if err != nil {
+func (m *Method) GetBlockCount(ctx context.Context) (resp *GetBlockCountResponse, err error) {
+	if err = m.Request(ctx, MethodGetBlockCount, nil, &resp); err != nil {
 		return nil, err
 	}
-
-	return resp, nil
+	m.cache.setTip(resp.Int64())
+	return
 }
 
-func (m *Method) GetBlockCount() (resp *GetBlockCountResponse, err error) {
-	err = m.Request(MethodGetBlockCount, nil, &resp)
+func (m *Method) GetBlockHash(ctx context.Context, b *big.Int) (resp GetBlockHashResponse, err error) {
+	req := GetBlockHashRequest{
+		Int: b,
+	}
+	err = m.cachedRequest(ctx, MethodGetBlockHash, &req, &resp, heightConfirmations(b.Int64()))
 	return
 }
 
-func (m *Method) GetBlockHash(b *big.Int) (resp GetBlockHashResponse, err error) {
+// GetBlockHashUncached is GetBlockHash bypassing the response cache, for callers which detect reorgs
+// deeper than the confirmations of the cached hashes
+func (m *Method) GetBlockHashUncached(ctx context.Context, b *big.Int) (resp GetBlockHashResponse, err error) {
 	req := GetBlockHashRequest{
 		Int: b,
 	}
-	err = m.Request(MethodGetBlockHash, &req, &resp)
+	err = m.Request(ctx, MethodGetBlockHash, &req, &resp)
 	return
 }
 
-func (m *Method) GetBlockHeader(hash string) (resp *GetBlockHeaderResponse, err error) {
+func (m *Method) GetBlockHeader(ctx context.Context, hash string) (resp *GetBlockHeaderResponse, err error) {
 	req := GetBlockHeaderRequest{
 		Hash: hash,
 	}
-	err = m.Request(MethodGetBlockHeader, &req, &resp)
+	err = m.cachedRequest(ctx, MethodGetBlockHeader, &req, &resp, confirmationsField)
 	return
 }
 
-func (m *Method) GetBlock(hash string) (resp *GetBlockResponse, err error) {
+func (m *Method) GetBlock(ctx context.Context, hash string) (resp *GetBlockResponse, err error) {
 	req := GetBlockRequest{
 		Hash: hash,
 	}
-	err = m.Request(MethodGetBlock, &req, &resp)
+	err = m.cachedRequest(ctx, MethodGetBlock, &req, &resp, confirmationsField)
+	return
+}
+
+func (m *Method) GetBlockWithTransactions(ctx context.Context, hash string) (resp *GetBlockWithTransactionsResponse, err error) {
+	verbosity := 2
+	req := GetBlockRequest{
+		Hash:      hash,
+		Verbosity: &verbosity,
+	}
+	err = m.cachedRequest(ctx, MethodGetBlock, &req, &resp, confirmationsField)
 	return
 }
 
-func (m *Method) Generate(blockNum int, maxTries *int) (resp GenerateResponse, err error) {
+func (m *Method) Generate(ctx context.Context, blockNum int, maxTries *int) (resp GenerateResponse, err error) {
 	req := GenerateRequest{
 		BlockNum: blockNum,
 		MaxTries: maxTries,
 	}
-	err = m.Request(MethodGenerate, &req, &resp)
+	err = m.Request(ctx, MethodGenerate, &req, &resp)
+	return
+}
+
+func (m *Method) SearchLogs(ctx context.Context, req *SearchLogsRequest) (receipts SearchLogsResponse, err error) {
+	if err := m.Request(ctx, MethodSearchLogs, req, &receipts); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (m *Method) CallContract(ctx context.Context, req *CallContractRequest) (resp *CallContractResponse, err error) {
+	if err := m.Request(ctx, MethodCallContract, req, &resp); err != nil {
+		return nil, err
+	}
 	return
 }
 
-func (m *Method) SearchLogs(req *SearchLogsRequest) (receipts SearchLogsResponse, err error) {
-	if err := m.Request(MethodSearchLogs, req, &receipts); err != nil {
+func (m *Method) GetAccountInfo(ctx context.Context, req *GetAccountInfoRequest) (resp *GetAccountInfoResponse, err error) {
+	if err := m.Request(ctx, MethodGetAccountInfo, req, &resp); err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (m *Method) CallContract(req *CallContractRequest) (resp *CallContractResponse, err error) {
-	if err := m.Request(MethodCallContract, req, &resp); err != nil {
+func (m *Method) ListUnspent(ctx context.Context, req *ListUnspentRequest) (resp *ListUnspentResponse, err error) {
+	if err := m.Request(ctx, MethodListUnspent, req, &resp); err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (m *Method) GetAccountInfo(req *GetAccountInfoRequest) (resp *GetAccountInfoResponse, err error) {
-	if err := m.Request(MethodGetAccountInfo, req, &resp); err != nil {
+func (m *Method) GetRawMempool(ctx context.Context) (resp GetRawMempoolResponse, err error) {
+	err = m.Request(ctx, MethodGetRawMempool, nil, &resp)
+	return
+}
+
+func (m *Method) SignRawTransaction(ctx context.Context, hex string) (resp *SignRawTransactionResponse, err error) {
+	if err := m.Request(ctx, MethodSignRawTransaction, SignRawTransactionRequest(hex), &resp); err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (m *Method) ListUnspent(req *ListUnspentRequest) (resp *ListUnspentResponse, err error) {
-	if err := m.Request(MethodListUnspent, req, &resp); err != nil {
+func (m *Method) SendRawTransaction(ctx context.Context, hex string) (resp SendRawTransactionResponse, err error) {
+	err = m.Request(ctx, MethodSendRawTransaction, SendRawTransactionRequest(hex), &resp)
+	return
+}
+
+func (m *Method) GetDGPInfo(ctx context.Context) (resp *GetDGPInfoResponse, err error) {
+	err = m.Request(ctx, MethodGetDGPInfo, nil, &resp)
+	return
+}
+
+func (m *Method) GetRawTransaction(ctx context.Context, txid string) (resp *DecodedRawTransactionResponse, err error) {
+	req := GetRawTransactionRequest{
+		Txid:    txid,
+		Verbose: true,
+	}
+	err = m.cachedRequest(ctx, MethodGetRawTransaction, &req, &resp, confirmationsField)
+	return
+}
+
+func (m *Method) GetAddressDeltas(ctx context.Context, req *GetAddressDeltasRequest) (resp GetAddressDeltasResponse, err error) {
+	err = m.Request(ctx, MethodGetAddressDeltas, req, &resp)
+	return
+}
+
+func (m *Method) GetAddressMempool(ctx context.Context, req *GetAddressMempoolRequest) (resp GetAddressMempoolResponse, err error) {
+	err = m.Request(ctx, MethodGetAddressMempool, req, &resp)
+	return
+}
+
+func (m *Method) GetBlockChainInfo(ctx context.Context) (resp *GetBlockChainInfoResponse, err error) {
+	if err = m.Request(ctx, MethodGetBlockChainInfo, nil, &resp); err != nil {
 		return nil, err
 	}
+	m.cache.setTip(resp.Blocks)
+	return
+}
+
+func (m *Method) GetTransaction(ctx context.Context, txid string) (resp *GetTransactionResponse, err error) {
+	req := GetTransactionRequest{
+		Txid: txid,
+	}
+	err = m.cachedRequest(ctx, MethodGetTransaction, &req, &resp, confirmationsField)
+	return
+}
+
+func (m *Method) GetWalletInfo(ctx context.Context) (resp *GetWalletInfoResponse, err error) {
+	err = m.Request(ctx, MethodGetWalletInfo, nil, &resp)
 	return
 }
+
+func (m *Method) GetNetworkInfo(ctx context.Context) (resp *GetNetworkInfoResponse, err error) {
+	err = m.Request(ctx, MethodGetNetworkInfo, nil, &resp)
+	return
+}
+
+func (m *Method) GetConnectionCount(ctx context.Context) (resp GetConnectionCountResponse, err error) {
+	err = m.Request(ctx, MethodGetConnectionCount, nil, &resp)
+	return
+}
+
+func (m *Method) GetMiningInfo(ctx context.Context) (resp *GetMiningInfoResponse, err error) {
+	err = m.Request(ctx, MethodGetMiningInfo, nil, &resp)
+	return
+}
+
+func (m *Method) GetStakingInfo(ctx context.Context) (resp *GetStakingInfoResponse, err error) {
+	err = m.Request(ctx, MethodGetStakingInfo, nil, &resp)
+	return
+}
+
+func (m *Method) InvalidateBlock(ctx context.Context, hash string) error {
+	var resp json.RawMessage
+	return m.Request(ctx, MethodInvalidateBlock, InvalidateBlockRequest(hash), &resp)
+}
+
+func (m *Method) ReconsiderBlock(ctx context.Context, hash string) error {
+	var resp json.RawMessage
+	return m.Request(ctx, MethodReconsiderBlock, ReconsiderBlockRequest(hash), &resp)
+}
+
+// SetMockTime sets the clock of qtumd to the unix time t, 0 goes back to the system clock, regtest only
+func (m *Method) SetMockTime(ctx context.Context, t int64) error {
+	var resp json.RawMessage
+	return m.Request(ctx, MethodSetMockTime, SetMockTimeRequest(t), &resp)
+}
+
+func (m *Method) PrioritiseTransaction(ctx context.Context, req *PrioritiseTransactionRequest) error {
+	var resp json.RawMessage
+	return m.Request(ctx, MethodPrioritiseTransaction, req, &resp)
+}
@@ -0,0 +1,172 @@
+package qtum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+// upstream is a qtumd node calls are sent to
+type upstream struct {
+	url string
+
+	mutex   sync.Mutex
+	healthy bool
+}
+
+func (u *upstream) isHealthy() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return u.healthy
+}
+
+// setHealthy returns whether the health of u changed
+func (u *upstream) setHealthy(healthy bool) bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	changed := u.healthy != healthy
+	u.healthy = healthy
+	return changed
+}
+
+type walletNodeKey struct{}
+
+// WithWalletNode pins the calls made with ctx to the wallet node, for reads which must see the effects of
+// earlier calls to it, e.g. the mempool after sendrawtransaction
+func WithWalletNode(ctx context.Context) context.Context {
+	return context.WithValue(ctx, walletNodeKey{}, true)
+}
+
+// upstreamFor returns the node method is sent to, wallet methods and the calls of WithWalletNode are pinned to
+// the wallet node, other methods are spread over the healthy nodes
+func (c *Client) upstreamFor(ctx context.Context, method string) *upstream {
+	pinned, _ := ctx.Value(walletNodeKey{}).(bool)
+	if len(c.upstreams) == 1 || isWalletMethod(method) || pinned {
+		return c.upstreams[0]
+	}
+
+	healthy := make([]*upstream, 0, len(c.upstreams))
+	for _, u := range c.upstreams {
+		if u.isHealthy() {
+			healthy = append(healthy, u)
+		}
+	}
+	if len(healthy) == 0 {
+		// try anyway, the nodes may be back before the next health check
+		healthy = c.upstreams
+	}
+
+	i := atomic.AddUint64(&c.nextUpstream, 1)
+	return healthy[i%uint64(len(healthy))]
+}
+
+// markDown takes u out of rotation after a connection error until a health check succeeds
+func (c *Client) markDown(u *upstream, err error) {
+	if len(c.upstreams) > 1 && u.setHealthy(false) {
+		level.Warn(c.logger).Log("msg", "qtum upstream is down", "upstream", redactURL(u.url), "err", err.Error())
+	}
+}
+
+func (c *Client) healthCheckLoop() {
+	ticker := time.NewTicker(c.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), c.healthCheckInterval)
+		c.checkUpstreams(ctx)
+		cancel()
+
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkUpstreams marks the nodes which are reachable and at most maxLag blocks behind the best node as healthy
+func (c *Client) checkUpstreams(ctx context.Context) {
+	heights := make([]int64, len(c.upstreams))
+	errs := make([]error, len(c.upstreams))
+
+	var wg sync.WaitGroup
+	for i, u := range c.upstreams {
+		wg.Add(1)
+		go func(i int, u *upstream) {
+			defer wg.Done()
+			heights[i], errs[i] = c.upstreamHeight(ctx, u)
+		}(i, u)
+	}
+	wg.Wait()
+
+	var best int64
+	for i := range c.upstreams {
+		if errs[i] == nil && heights[i] > best {
+			best = heights[i]
+		}
+	}
+
+	for i, u := range c.upstreams {
+		err := errs[i]
+		if err == nil && best-heights[i] > c.maxLag {
+			err = errors.Errorf("%d blocks behind", best-heights[i])
+		}
+
+		if !u.setHealthy(err == nil) {
+			continue
+		}
+		if err != nil {
+			level.Warn(c.logger).Log("msg", "qtum upstream is down", "upstream", redactURL(u.url), "err", err.Error())
+		} else {
+			level.Info(c.logger).Log("msg", "qtum upstream is up", "upstream", redactURL(u.url), "height", heights[i])
+		}
+	}
+}
+
+func (c *Client) upstreamHeight(ctx context.Context, u *upstream) (int64, error) {
+	req, err := c.NewRPCRequest(MethodGetBlockCount, nil)
+	if err != nil {
+		return 0, err
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+
+	respBody, err := c.do(ctx, u.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	res, err := responseBodyToResult(respBody)
+	if err != nil {
+		return 0, err
+	}
+
+	var height int64
+	err = json.Unmarshal(res.RawResult, &height)
+	return height, err
+}
+
+// Close stops the health checks of the upstreams
+func (c *Client) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+// redactURL drops the credentials of an upstream URL, so it can be logged
+func redactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	return u.String()
+}
@@ -1,6 +1,8 @@
 package qtum
 
 import (
+	"math/big"
+
 	"github.com/dcb9/janus/pkg/utils"
 	"github.com/pkg/errors"
 )
@@ -8,7 +10,10 @@ import (
 type Qtum struct {
 	*Client
 	*Method
-	chain string
+	chain   string
+	chainID int64
+	// how many wei of Ethereum clients are a satoshi
+	weiPerSatoshi *big.Int
 }
 
 const (
@@ -19,18 +24,67 @@ const (
 
 var AllChains = []string{ChainMain, ChainRegTest, ChainTest}
 
-func New(c *Client, chain string) (*Qtum, error) {
+// DefaultChainIDs are the EIP-155 chain IDs of the qtum networks
+var DefaultChainIDs = map[string]int64{
+	ChainMain: 81,
+	ChainTest: 8889,
+	// 0x1024, the network ID janus always reported for regtest
+	ChainRegTest: 4132,
+}
+
+func New(c *Client, chain string, opts ...func(*Qtum) error) (*Qtum, error) {
 	if !utils.InStrSlice(AllChains, chain) {
 		return nil, errors.New("invalid qtum chain")
 	}
 
-	return &Qtum{
-		Client: c,
-		Method: &Method{Client: c},
-		chain:  chain,
-	}, nil
+	q := &Qtum{
+		Client:  c,
+		Method:  &Method{Client: c},
+		chain:   chain,
+		chainID: DefaultChainIDs[chain],
+
+		weiPerSatoshi: big.NewInt(1),
+	}
+
+	for _, opt := range opts {
+		if err := opt(q); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// SetChainID overrides the chain ID of the network, 0 keeps the default one
+func SetChainID(id int64) func(*Qtum) error {
+	return func(q *Qtum) error {
+		if id < 0 {
+			return errors.New("chain ID cannot be negative")
+		}
+		if id > 0 {
+			q.chainID = id
+		}
+		return nil
+	}
+}
+
+// SetWeiPerSatoshi sets how many wei of Ethereum clients are a satoshi, 1 by default,
+// e.g. with 1e10 wei per satoshi 1 QTUM is 1e18 wei like 1 ETH
+func SetWeiPerSatoshi(wei *big.Int) func(*Qtum) error {
+	return func(q *Qtum) error {
+		if wei == nil || wei.Sign() <= 0 {
+			return errors.New("wei per satoshi must be positive")
+		}
+		q.weiPerSatoshi = new(big.Int).Set(wei)
+		return nil
+	}
 }
 
 func (c *Qtum) Chain() string {
 	return c.chain
 }
+
+// ChainID returns the chain ID eth_chainId and net_version report
+func (c *Qtum) ChainID() int64 {
+	return c.chainID
+}
@@ -96,6 +96,7 @@ type (
 			Version int64 `json:"version"`
 		} `json:"softforks"`
 		Verificationprogress float64 `json:"verificationprogress"`
+		InitialBlockDownload bool    `json:"initialblockdownload"`
 	}
 )
 
@@ -104,7 +105,7 @@ type (
 type (
 	SendToAddressRequest struct {
 		Address       string
-		Amount        float64
+		Amount        Amount
 		SenderAddress string
 	}
 	SendToAddressResponse string
@@ -150,9 +151,9 @@ type (
 	SendToContractRequest struct {
 		ContractAddress string
 		Datahex         string
-		Amount          float64
+		Amount          Amount
 		GasLimit        *big.Int
-		GasPrice        string
+		GasPrice        Amount
 		SenderAddress   string
 	}
 	/*
@@ -197,7 +198,7 @@ type (
 	CreateContractRequest struct {
 		ByteCode      string
 		GasLimit      *big.Int
-		GasPrice      string
+		GasPrice      Amount
 		SenderAddress string
 	}
 	/*
@@ -294,32 +295,6 @@ func (r *CallContractRequest) MarshalJSON() ([]byte, error) {
 	})
 }
 
-// ========== FromHexAddress ============= //
-
-type (
-	FromHexAddressRequest  string
-	FromHexAddressResponse string
-)
-
-func (r FromHexAddressRequest) MarshalJSON() ([]byte, error) {
-	return json.Marshal([]interface{}{
-		string(r),
-	})
-}
-
-// ========== GetHexAddress ============= //
-
-type (
-	GetHexAddressRequest  string
-	GetHexAddressResponse string
-)
-
-func (r GetHexAddressRequest) MarshalJSON() ([]byte, error) {
-	return json.Marshal([]interface{}{
-		string(r),
-	})
-}
-
 // ========== DecodeRawTransaction ============= //
 func (r DecodeRawTransactionRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal([]interface{}{
@@ -397,8 +372,8 @@ type (
 	}
 
 	DecodedRawTransactionOutV struct {
-		Value        float64 `json:"value"`
-		N            int64   `json:"n"`
+		Value        Amount `json:"value"`
+		N            int64  `json:"n"`
 		ScriptPubKey struct {
 			Asm       string   `json:"asm"`
 			Hex       string   `json:"hex"`
@@ -425,6 +400,7 @@ type (
 	     "gasUsed": 68572,
 	     "contractAddress": "db46f738bf32cdafb9a4a70eb8b44c76646bcaf0",
 	     "excepted": "None",
+	     "exceptedMessage": "",
 	     "log": [
 	       {
 	         "address": "db46f738bf32cdafb9a4a70eb8b44c76646bcaf0",
@@ -457,6 +433,7 @@ type (
 		GasUsed           uint64 `json:"gasUsed"`
 		ContractAddress   string `json:"contractAddress"`
 		Excepted          string `json:"excepted"`
+		ExceptedMessage   string `json:"exceptedMessage"` // the reason of a revert, decoded by qtumd
 		Log               []Log  `json:"log"`
 	}
 )
@@ -541,8 +518,8 @@ type (
 		  }
 	*/
 	GetTransactionResponse struct {
-		Amount            float64              `json:"amount"`
-		Fee               float64              `json:"fee"`
+		Amount            Amount               `json:"amount"`
+		Fee               Amount               `json:"fee"`
 		Confirmations     int64                `json:"confirmations"`
 		Blockhash         string               `json:"blockhash"`
 		Blockindex        int64                `json:"blockindex"`
@@ -555,14 +532,14 @@ type (
 		Hex               string               `json:"hex"`
 	}
 	TransactionDetail struct {
-		Account   string  `json:"account"`
-		Address   string  `json:"address"`
-		Category  string  `json:"category"`
-		Amount    float64 `json:"amount"`
-		Label     string  `json:"label"`
-		Vout      int64   `json:"vout"`
-		Fee       float64 `json:"fee"`
-		Abandoned bool    `json:"abandoned"`
+		Account   string `json:"account"`
+		Address   string `json:"address"`
+		Category  string `json:"category"`
+		Amount    Amount `json:"amount"`
+		Label     string `json:"label"`
+		Vout      int64  `json:"vout"`
+		Fee       Amount `json:"fee"`
+		Abandoned bool   `json:"abandoned"`
 	}
 )
 
@@ -857,6 +834,12 @@ type (
 	}
 )
 
+// GetBlockWithTransactionsResponse is the result of getblock with verbosity 2
+type GetBlockWithTransactionsResponse struct {
+	GetBlockResponse
+	Tx []*DecodedRawTransactionResponse `json:"tx"`
+}
+
 func (r *GetBlockRequest) MarshalJSON() ([]byte, error) {
 	verbosity := 1
 	if r.Verbosity != nil {
@@ -933,16 +916,16 @@ type (
 		]
 	*/
 	ListUnspentResponse []struct {
-		Txid          string  `json:"txid"`
-		Vout          int     `json:"vout"`
-		Address       string  `json:"address"`
-		Account       string  `json:"account"`
-		ScriptPubKey  string  `json:"scriptPubKey"`
-		Amount        float64 `json:"amount"`
-		Confirmations int     `json:"confirmations"`
-		Spendable     bool    `json:"spendable"`
-		Solvable      bool    `json:"solvable"`
-		Safe          bool    `json:"safe"`
+		Txid          string `json:"txid"`
+		Vout          int    `json:"vout"`
+		Address       string `json:"address"`
+		Account       string `json:"account"`
+		ScriptPubKey  string `json:"scriptPubKey"`
+		Amount        Amount `json:"amount"`
+		Confirmations int    `json:"confirmations"`
+		Spendable     bool   `json:"spendable"`
+		Solvable      bool   `json:"solvable"`
+		Safe          bool   `json:"safe"`
 	}
 )
 
@@ -961,3 +944,373 @@ func (r *ListUnspentRequest) MarshalJSON() ([]byte, error) {
 		r.Addresses,
 	})
 }
+
+// ========== GetRawMempool ============= //
+type (
+	/*
+		[
+		  "a1ef2a4ec1fbc3e6c95c0a9ea1e07eb5a09e61e42e5cd2e56f3d7df7c5d4d0a1",
+		  ...
+		]
+	*/
+	GetRawMempoolResponse []string
+)
+
+// ========== SignRawTransaction ============= //
+type (
+	// the transaction hex string
+	SignRawTransactionRequest string
+
+	/*
+		{
+		  "hex" : "value",           (string) The hex-encoded raw transaction with signature(s)
+		  "complete" : true|false,   (boolean) If the transaction has a complete set of signatures
+		  "errors" : [               (json array of objects) Script verification errors (if there are any)
+		    {
+		      "txid" : "hash",           (string) The hash of the referenced, previous transaction
+		      "vout" : n,                (numeric) The index of the output to spent and used as input
+		      "scriptSig" : "hex",       (string) The hex-encoded signature script
+		      "sequence" : n,            (numeric) Script sequence number
+		      "error" : "text"           (string) Verification or signing error related to the input
+		    }
+		    ,...
+		  ]
+		}
+	*/
+	SignRawTransactionResponse struct {
+		Hex      string `json:"hex"`
+		Complete bool   `json:"complete"`
+		Errors   []struct {
+			Txid  string `json:"txid"`
+			Vout  int64  `json:"vout"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+)
+
+func (r SignRawTransactionRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. "hexstring"     (string, required) The transaction hex string
+	*/
+	return json.Marshal([]interface{}{
+		string(r),
+	})
+}
+
+// ========== SendRawTransaction ============= //
+type (
+	// the signed transaction hex string
+	SendRawTransactionRequest string
+
+	// the transaction hash in hex
+	SendRawTransactionResponse string
+)
+
+func (r SendRawTransactionRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. "hexstring"    (string, required) The hex string of the raw transaction)
+		2. allowhighfees    (boolean, optional, default=false) Allow high fees
+	*/
+	return json.Marshal([]interface{}{
+		string(r),
+	})
+}
+
+// ========== GetDGPInfo ============= //
+type (
+	/*
+		{
+		  "maxblocksize": 2000000,
+		  "mingasprice": 40,
+		  "blockgaslimit": 40000000
+		}
+	*/
+	GetDGPInfoResponse struct {
+		MaxBlockSize  uint64 `json:"maxblocksize"`
+		MinGasPrice   uint64 `json:"mingasprice"`
+		BlockGasLimit uint64 `json:"blockgaslimit"`
+	}
+)
+
+// ========== GetRawTransaction ============= //
+type (
+	// the verbose result is a DecodedRawTransactionResponse, requires -txindex for transactions not in the wallet
+	GetRawTransactionRequest struct {
+		Txid    string
+		Verbose bool
+	}
+)
+
+func (r *GetRawTransactionRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. "txid"      (string, required) The transaction id
+		2. verbose     (bool, optional, default=false) If false, return a string, otherwise return a json object
+	*/
+	return json.Marshal([]interface{}{
+		r.Txid,
+		r.Verbose,
+	})
+}
+
+// ========== GetAddressDeltas ============= //
+type (
+	// requires -addrindex
+	GetAddressDeltasRequest struct {
+		Addresses []string
+	}
+
+	/*
+		[
+		  {
+		    "satoshis": -100000000,
+		    "txid": "7c1d2e3a6c1e5f9cbb0ef4f4df8e3d1c6e8a8f1f2f3a4b5c6d7e8f9a0b1c2d3e",
+		    "index": 0,
+		    "blockindex": 1,
+		    "height": 4063,
+		    "address": "qUbxboqjBRp96j3La8D1RYkyqx5uQbJPoW"
+		  }
+		]
+	*/
+	GetAddressDeltasResponse []AddressDelta
+
+	AddressDelta struct {
+		Satoshis   int64  `json:"satoshis"`
+		Txid       string `json:"txid"`
+		Index      int64  `json:"index"`
+		BlockIndex int64  `json:"blockindex"`
+		Height     int64  `json:"height"`
+		Address    string `json:"address"`
+	}
+)
+
+func (r *GetAddressDeltasRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. {
+		     "addresses"  (array) The base58check encoded addresses
+		   }
+	*/
+	return json.Marshal([]interface{}{
+		map[string]interface{}{
+			"addresses": r.Addresses,
+		},
+	})
+}
+
+// ========== GetAddressMempool ============= //
+type (
+	// requires -addrindex
+	GetAddressMempoolRequest struct {
+		Addresses []string
+	}
+
+	/*
+		[
+		  {
+		    "address": "qUbxboqjBRp96j3La8D1RYkyqx5uQbJPoW",
+		    "txid": "7c1d2e3a6c1e5f9cbb0ef4f4df8e3d1c6e8a8f1f2f3a4b5c6d7e8f9a0b1c2d3e",
+		    "index": 0,
+		    "satoshis": -100000000,
+		    "timestamp": 1540000000,
+		    "prevtxid": "a1ef2a4ec1fbc3e6c95c0a9ea1e07eb5a09e61e42e5cd2e56f3d7df7c5d4d0a1",
+		    "prevout": 1
+		  }
+		]
+	*/
+	GetAddressMempoolResponse []AddressMempoolDelta
+
+	AddressMempoolDelta struct {
+		Address   string `json:"address"`
+		Txid      string `json:"txid"`
+		Index     int64  `json:"index"`
+		Satoshis  int64  `json:"satoshis"`
+		Timestamp int64  `json:"timestamp"`
+		Prevtxid  string `json:"prevtxid"`
+		Prevout   int64  `json:"prevout"`
+	}
+)
+
+func (r *GetAddressMempoolRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. {
+		     "addresses"  (array) The base58check encoded addresses
+		   }
+	*/
+	return json.Marshal([]interface{}{
+		map[string]interface{}{
+			"addresses": r.Addresses,
+		},
+	})
+}
+
+// ========== GetWalletInfo ============= //
+type (
+	/*
+		{
+		  "walletname": "wallet.dat",
+		  "walletversion": 169900,
+		  "balance": 2000000.00000000,
+		  "stake": 0.00000000,
+		  "unconfirmed_balance": 0.00000000,
+		  "immature_balance": 0.00000000,
+		  "txcount": 600,
+		  "keypoololdest": 1540000000,
+		  "keypoolsize": 1000,
+		  "unlocked_until": 0,
+		  "paytxfee": 0.00000000,
+		  "hdmasterkeyid": "e64b3f8b2e4ee1c3f4b7e6ae7b5b8c1f5dbf6d33"
+		}
+	*/
+	GetWalletInfoResponse struct {
+		WalletName string `json:"walletname"`
+		Balance    Amount `json:"balance"`
+		TxCount    int64  `json:"txcount"`
+		// only set if the wallet is encrypted, 0 if it is locked
+		UnlockedUntil *int64 `json:"unlocked_until"`
+	}
+)
+
+// IsLocked reports whether the wallet is encrypted and locked
+func (r *GetWalletInfoResponse) IsLocked() bool {
+	return r.UnlockedUntil != nil && *r.UnlockedUntil == 0
+}
+
+// ========== GetNetworkInfo ============= //
+
+type (
+	/*
+		{
+		  "version": 180100,
+		  "subversion": "/Satoshi:0.18.1/",
+		  "protocolversion": 70017,
+		  "localservices": "000000000000040d",
+		  "localrelay": true,
+		  "timeoffset": 0,
+		  "networkactive": true,
+		  "connections": 8,
+		  ...
+		}
+	*/
+	GetNetworkInfoResponse struct {
+		Version         int64  `json:"version"`
+		Subversion      string `json:"subversion"`
+		ProtocolVersion int64  `json:"protocolversion"`
+		NetworkActive   bool   `json:"networkactive"`
+		Connections     int64  `json:"connections"`
+	}
+
+	GetConnectionCountResponse int64
+)
+
+// ========== GetMiningInfo ============= //
+
+type (
+	/*
+		{
+		  "blocks": 4000,
+		  "currentblockweight": 0,
+		  "currentblocktx": 0,
+		  "difficulty": {
+		    "proof-of-work": 1.52587890625e-05,
+		    "proof-of-stake": 1.52587890625e-05
+		  },
+		  "networkhashps": 1.35613e+09,
+		  "netstakeweight": 1356130000,
+		  "chain": "regtest",
+		  ...
+		}
+	*/
+	GetMiningInfoResponse struct {
+		Blocks         int64   `json:"blocks"`
+		NetworkHashPS  float64 `json:"networkhashps"`
+		NetStakeWeight float64 `json:"netstakeweight"`
+		Chain          string  `json:"chain"`
+	}
+
+	/*
+		{
+		  "enabled": true,
+		  "staking": false,
+		  "errors": "",
+		  "pooledtx": 0,
+		  "difficulty": 1.52587890625e-05,
+		  "search-interval": 0,
+		  "weight": 0,
+		  "netstakeweight": 0,
+		  "expectedtime": 0
+		}
+	*/
+	GetStakingInfoResponse struct {
+		Enabled        bool    `json:"enabled"`
+		Staking        bool    `json:"staking"`
+		Weight         int64   `json:"weight"`
+		NetStakeWeight float64 `json:"netstakeweight"`
+	}
+)
+
+// ========== InvalidateBlock ============= //
+type (
+	// the hash of the block, it and its descendants are marked invalid
+	InvalidateBlockRequest string
+)
+
+func (r InvalidateBlockRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. "blockhash"   (string, required) the hash of the block to mark as invalid
+	*/
+	return json.Marshal([]interface{}{
+		string(r),
+	})
+}
+
+// ========== ReconsiderBlock ============= //
+type (
+	// the hash of the block, it and its descendants are not invalid anymore
+	ReconsiderBlockRequest string
+)
+
+func (r ReconsiderBlockRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. "blockhash"   (string, required) the hash of the block to reconsider
+	*/
+	return json.Marshal([]interface{}{
+		string(r),
+	})
+}
+
+// ========== SetMockTime ============= //
+type (
+	// unix time in seconds
+	SetMockTimeRequest int64
+)
+
+func (r SetMockTimeRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. timestamp  (integer, required) Unix seconds-since-epoch timestamp
+		   Pass 0 to go back to using the system time.
+	*/
+	return json.Marshal([]interface{}{
+		int64(r),
+	})
+}
+
+// ========== PrioritiseTransaction ============= //
+type (
+	PrioritiseTransactionRequest struct {
+		Txid string
+		// satoshis added to the fee of the transaction when blocks are mined, it can be negative
+		FeeDelta int64
+	}
+)
+
+func (r *PrioritiseTransactionRequest) MarshalJSON() ([]byte, error) {
+	/*
+		1. "txid"       (string, required) The transaction id.
+		2. dummy        (numeric, optional) API-Compatibility for previous API. Must be zero or null.
+		3. fee_delta    (numeric, required) The fee value (in satoshis) to add (or subtract, if negative).
+	*/
+	return json.Marshal([]interface{}{
+		r.Txid,
+		0,
+		r.FeeDelta,
+	})
+}
@@ -0,0 +1,167 @@
+package qtum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Script opcodes used by Janus, Qtum extends Bitcoin Script with OP_CREATE and OP_CALL
+const (
+	OpDup         = 0x76
+	OpHash160     = 0xa9
+	OpEqualVerify = 0x88
+	OpCheckSig    = 0xac
+	OpPushData1   = 0x4c
+	OpPushData2   = 0x4d
+	OpPushData4   = 0x4e
+	OpCreate      = 0xc1
+	OpCall        = 0xc2
+)
+
+// VMVersion of the EVM, see: https://github.com/qtumproject/qtum/blob/master/src/qtum/qtumtransaction.h
+const VMVersionEVM = 4
+
+type (
+	// Transaction is an unsigned Qtum transaction, serialized in the Bitcoin wire format
+	Transaction struct {
+		Version  int32
+		Inputs   []*TxInput
+		Outputs  []*TxOutput
+		LockTime uint32
+	}
+
+	TxInput struct {
+		Txid     string // hex, as displayed by qtumd
+		Vout     uint32
+		Sequence uint32
+	}
+
+	TxOutput struct {
+		Value        int64 // in satoshi
+		ScriptPubKey []byte
+	}
+)
+
+func NewTransaction() *Transaction {
+	return &Transaction{Version: 2}
+}
+
+func (tx *Transaction) AddInput(txid string, vout uint32) {
+	tx.Inputs = append(tx.Inputs, &TxInput{Txid: txid, Vout: vout, Sequence: 0xffffffff})
+}
+
+func (tx *Transaction) AddOutput(value int64, script []byte) {
+	tx.Outputs = append(tx.Outputs, &TxOutput{Value: value, ScriptPubKey: script})
+}
+
+// Serialize returns the transaction with empty scriptSigs, ready to be signed by qtumd
+func (tx *Transaction) Serialize() ([]byte, error) {
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, tx.Version)
+
+	writeVarInt(&buf, uint64(len(tx.Inputs)))
+	for _, in := range tx.Inputs {
+		txid, err := hex.DecodeString(in.Txid)
+		if err != nil || len(txid) != 32 {
+			return nil, errors.Errorf("invalid txid: %s", in.Txid)
+		}
+		// txids are displayed in reversed byte order
+		for i := len(txid) - 1; i >= 0; i-- {
+			buf.WriteByte(txid[i])
+		}
+		binary.Write(&buf, binary.LittleEndian, in.Vout)
+		writeVarInt(&buf, 0)
+		binary.Write(&buf, binary.LittleEndian, in.Sequence)
+	}
+
+	writeVarInt(&buf, uint64(len(tx.Outputs)))
+	for _, out := range tx.Outputs {
+		binary.Write(&buf, binary.LittleEndian, out.Value)
+		writeVarInt(&buf, uint64(len(out.ScriptPubKey)))
+		buf.Write(out.ScriptPubKey)
+	}
+
+	binary.Write(&buf, binary.LittleEndian, tx.LockTime)
+
+	return buf.Bytes(), nil
+}
+
+// P2PKHScript pays to the 20 bytes public key hash
+func P2PKHScript(pubKeyHash []byte) []byte {
+	script := []byte{OpDup, OpHash160}
+	script = appendPushData(script, pubKeyHash)
+	return append(script, OpEqualVerify, OpCheckSig)
+}
+
+// ContractCreateScript is the script of an OP_CREATE output, see: ParseCreateASM
+func ContractCreateScript(gasLimit, gasPrice *big.Int, bytecode []byte) []byte {
+	script := appendPushData(nil, scriptNum(big.NewInt(VMVersionEVM)))
+	script = appendPushData(script, scriptNum(gasLimit))
+	script = appendPushData(script, scriptNum(gasPrice))
+	script = appendPushData(script, bytecode)
+	return append(script, OpCreate)
+}
+
+// ContractCallScript is the script of an OP_CALL output, see: ParseCallASM
+func ContractCallScript(gasLimit, gasPrice *big.Int, data, contractAddress []byte) []byte {
+	script := appendPushData(nil, scriptNum(big.NewInt(VMVersionEVM)))
+	script = appendPushData(script, scriptNum(gasLimit))
+	script = appendPushData(script, scriptNum(gasPrice))
+	script = appendPushData(script, data)
+	script = appendPushData(script, contractAddress)
+	return append(script, OpCall)
+}
+
+// scriptNum encodes a non negative number as CScriptNum, i.e. minimal little endian with a sign bit
+func scriptNum(n *big.Int) []byte {
+	if n.Sign() == 0 {
+		return []byte{}
+	}
+
+	be := n.Bytes()
+	le := make([]byte, len(be))
+	for i, b := range be {
+		le[len(be)-1-i] = b
+	}
+
+	// the most significant bit is the sign bit
+	if le[len(le)-1]&0x80 != 0 {
+		le = append(le, 0)
+	}
+	return le
+}
+
+func appendPushData(script, data []byte) []byte {
+	switch n := len(data); {
+	case n < OpPushData1:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, OpPushData1, byte(n))
+	case n <= 0xffff:
+		script = append(script, OpPushData2, byte(n), byte(n>>8))
+	default:
+		script = append(script, OpPushData4, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	}
+	return append(script, data...)
+}
+
+func writeVarInt(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xfd)
+		binary.Write(buf, binary.LittleEndian, uint16(n))
+	case n <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		binary.Write(buf, binary.LittleEndian, uint32(n))
+	default:
+		buf.WriteByte(0xff)
+		binary.Write(buf, binary.LittleEndian, n)
+	}
+}
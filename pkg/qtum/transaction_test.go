@@ -0,0 +1,19 @@
+package qtum
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestContractCallScript(t *testing.T) {
+	data, _ := hex.DecodeString("60fe47b10000000000000000000000000000000000000000000000000000000000000002")
+	addr, _ := hex.DecodeString("cd20af1f2d6ac4173f9464030e7cef40bf9cb7c4")
+
+	// 4 250000 40 60fe47b1...02 cd20af1f...c4 OP_CALL
+	want := "010403" + "90d003" + "0128" + "24" + hex.EncodeToString(data) + "14" + hex.EncodeToString(addr) + "c2"
+	got := hex.EncodeToString(ContractCallScript(big.NewInt(250000), big.NewInt(40), data, addr))
+	if got != want {
+		t.Errorf("want: %s, got: %s", want, got)
+	}
+}
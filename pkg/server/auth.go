@@ -0,0 +1,160 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+)
+
+// AuthConfig configures who can call Janus, e.g.
+//
+//	{
+//	  "jwtSecret": "secret used to sign HS256 tokens",
+//	  "keys": [
+//	    {"name": "dapp", "key": "8f3c...", "methods": ["eth_call", "eth_get*", "eth_blockNumber"]},
+//	    {"name": "deployer", "key": "b71a...", "methods": ["*"]}
+//	  ]
+//	}
+//
+// Clients send a key or a JWT as "Authorization: Bearer <token>", or as the token query parameter
+// since browsers cannot set headers of websocket connections. The methods claim of a JWT is its allowlist.
+type AuthConfig struct {
+	// HS256 secret of JWTs, JWTs are rejected if it is empty
+	JWTSecret string   `json:"jwtSecret"`
+	Keys      []APIKey `json:"keys"`
+}
+
+// APIKey is a static key which can only call its Methods,
+// a method ending with * matches every method with that prefix, e.g. eth_get*
+type APIKey struct {
+	Name    string   `json:"name"`
+	Key     string   `json:"key"`
+	Methods []string `json:"methods"`
+}
+
+// authClaims are the claims of JWTs, the subject names the client
+type authClaims struct {
+	Methods []string `json:"methods"`
+	jwt.StandardClaims
+}
+
+// LoadAuthConfig reads an AuthConfig from a JSON file
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read auth config")
+	}
+
+	var config AuthConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "parse auth config")
+	}
+
+	return &config, config.validate()
+}
+
+func (c *AuthConfig) validate() error {
+	if c.JWTSecret == "" && len(c.Keys) == 0 {
+		return errors.New("auth config has neither a jwt secret nor keys")
+	}
+	for i, key := range c.Keys {
+		if key.Key == "" {
+			return errors.Errorf("key #%d (%s) is empty", i, key.Name)
+		}
+	}
+	return nil
+}
+
+// allowlist is the methods an authenticated client can call
+type allowlist struct {
+	name    string
+	methods []string
+}
+
+func (a *allowlist) allows(method string) bool {
+	for _, m := range a.methods {
+		if m == method || (strings.HasSuffix(m, "*") && strings.HasPrefix(method, strings.TrimSuffix(m, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate returns the allowlist of the key or JWT of the request
+func (c *AuthConfig) authenticate(req *http.Request) (*allowlist, error) {
+	token := req.URL.Query().Get("token")
+	if auth := req.Header.Get(echo.HeaderAuthorization); auth != "" {
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return nil, errors.New("the authorization header must be a bearer token")
+		}
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return nil, errors.New("missing credentials")
+	}
+
+	for _, key := range c.Keys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) == 1 {
+			return &allowlist{name: key.Name, methods: key.Methods}, nil
+		}
+	}
+
+	if c.JWTSecret == "" {
+		return nil, errors.New("invalid credentials")
+	}
+
+	var claims authClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, errors.Errorf("unexpected signing method %s", t.Header["alg"])
+		}
+		return []byte(c.JWTSecret), nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid credentials")
+	}
+
+	return &allowlist{name: claims.Subject, methods: claims.Methods}, nil
+}
+
+// authMiddleware rejects requests without valid credentials, the methods are checked per request by myCtx.authorize
+func (s *Server) authMiddleware(h echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if s.auth == nil {
+			return h(c)
+		}
+
+		switch c.Path() {
+		case "/metrics", "/healthz", "/readyz":
+			return h(c)
+		}
+
+		cc, ok := c.Get("myctx").(*myCtx)
+		if !ok {
+			return errors.New("Could not find myctx")
+		}
+
+		allowlist, err := s.auth.authenticate(c.Request())
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, newJSONRPCErrorResult(nil, eth.NewInvalidRequestError(err.Error())))
+		}
+		cc.allowlist = allowlist
+
+		return h(c)
+	}
+}
+
+// authorize returns an error if the client is not allowed to call method
+func (c *myCtx) authorize(method string) error {
+	if c.allowlist == nil || c.allowlist.allows(method) {
+		return nil
+	}
+	return eth.NewMethodNotAllowedError(method)
+}
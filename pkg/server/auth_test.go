@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestAllowlist(t *testing.T) {
+	a := &allowlist{methods: []string{"eth_call", "eth_get*"}}
+
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"eth_call", true},
+		{"eth_getBalance", true},
+		{"eth_getLogs", true},
+		{"eth_sendTransaction", false},
+		{"personal_unlockAccount", false},
+		{"eth_callx", false},
+	}
+	for _, tt := range tests {
+		if got := a.allows(tt.method); got != tt.want {
+			t.Errorf("in: %s, want: %t, got: %t", tt.method, tt.want, got)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	config := &AuthConfig{
+		JWTSecret: "secret",
+		Keys:      []APIKey{{Name: "dapp", Key: "dappkey", Methods: []string{"eth_call"}}},
+	}
+
+	sign := func(secret string, claims authClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	validJWT := sign("secret", authClaims{Methods: []string{"eth_blockNumber"}, StandardClaims: jwt.StandardClaims{Subject: "explorer"}})
+	expiredJWT := sign("secret", authClaims{StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Hour).Unix()}})
+	forgedJWT := sign("guess", authClaims{Methods: []string{"*"}})
+
+	tests := []struct {
+		header string
+		query  string
+		want   string // name of the client, empty if the request is rejected
+	}{
+		{header: "Bearer dappkey", want: "dapp"},
+		{query: "dappkey", want: "dapp"},
+		{header: "Bearer " + validJWT, want: "explorer"},
+		{header: "Bearer " + expiredJWT},
+		{header: "Bearer " + forgedJWT},
+		{header: "Bearer wrongkey"},
+		{header: "Basic dappkey"},
+		{},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/?token="+tt.query, nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+
+		allowlist, err := config.authenticate(req)
+		got := ""
+		if err == nil {
+			got = allowlist.name
+		}
+		if got != tt.want {
+			t.Errorf("in: %s%s, want: %s, got: %s (err: %v)", tt.header, tt.query, tt.want, got, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/metrics"
+	"github.com/go-kit/kit/log/level"
+)
+
+var batchSize = metrics.NewHistogramVec("janus_batch_size", "Number of requests in JSON-RPC batches", []float64{1, 2, 5, 10, 20, 50, 100, 200})
+
+// batchOptions limits how batches are processed
+type batchOptions struct {
+	// the maximum number of requests in a batch, 0 means unlimited
+	maxSize int
+	// how many requests of a batch are processed at once
+	concurrency int
+}
+
+func isBatchRequests(msg json.RawMessage) bool {
+	return len(msg) > 0 && msg[0] == '['
+}
+
+// processBatch processes the requests of a batch concurrently, see: https://www.jsonrpc.org/specification#batch
+//
+// It returns the responses in the order of the requests. Notifications (requests without id) are processed,
+// but they have no response, so it returns nil if the batch only has notifications.
+// An invalid batch gets a single error response.
+func processBatch(opts batchOptions, msg []byte, handle func(*eth.JSONRPCRequest) *eth.JSONRPCResult) interface{} {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(msg, &entries); err != nil {
+		return newJSONRPCErrorResult(nil, eth.NewParseError(err.Error()))
+	}
+	if len(entries) == 0 {
+		return newJSONRPCErrorResult(nil, eth.NewInvalidRequestError("empty batch"))
+	}
+	if opts.maxSize > 0 && len(entries) > opts.maxSize {
+		return newJSONRPCErrorResult(nil, eth.NewInvalidRequestError(fmt.Sprintf("batch of %d requests exceeds the limit of %d", len(entries), opts.maxSize)))
+	}
+
+	batchSize.Observe(float64(len(entries)))
+
+	results := make([]*eth.JSONRPCResult, len(entries))
+	notifications := make([]bool, len(entries))
+
+	processEntry := func(i int) {
+		var rpcReq *eth.JSONRPCRequest
+		if err := json.Unmarshal(entries[i], &rpcReq); err != nil {
+			results[i] = newJSONRPCErrorResult(nil, eth.NewInvalidRequestError(err.Error()))
+			return
+		}
+		if rpcReq == nil {
+			results[i] = newJSONRPCErrorResult(nil, eth.NewInvalidRequestError("method must be set"))
+			return
+		}
+		if rpcReq.Method == "" {
+			results[i] = newJSONRPCErrorResult(rpcReq.ID, eth.NewInvalidRequestError("method must be set"))
+			return
+		}
+
+		notifications[i] = isNotification(rpcReq)
+		results[i] = handle(rpcReq)
+	}
+
+	concurrency := opts.concurrency
+	if concurrency <= 0 || concurrency > len(entries) {
+		concurrency = len(entries)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				processEntry(i)
+			}
+		}()
+	}
+	for i := range entries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	responses := make([]*eth.JSONRPCResult, 0, len(results))
+	for i, result := range results {
+		if !notifications[i] {
+			responses = append(responses, result)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+
+	return responses
+}
+
+// isNotification returns whether rpcReq is a valid request without id, which has no response,
+// see https://www.jsonrpc.org/specification#notification
+func isNotification(rpcReq *eth.JSONRPCRequest) bool {
+	return rpcReq != nil && rpcReq.Method != "" && rpcReq.ID == nil
+}
+
+// transformRequest returns the response of a single request, errors are returned as JSON-RPC errors
+func (c *myCtx) transformRequest(rpcReq *eth.JSONRPCRequest) *eth.JSONRPCResult {
+	// calls to qtumd are canceled when the client goes away
+	rpcReq = rpcReq.WithContext(c.Request().Context())
+
+	level.Info(c.logger).Log("msg", "proxy RPC", "method", rpcReq.Method)
+
+	if err := c.admit(rpcReq); err != nil {
+		return newJSONRPCErrorResult(rpcReq.ID, err)
+	}
+
+	result, err := c.transformer.Transform(rpcReq)
+	if err != nil {
+		level.Error(c.logger).Log("err", err.Error())
+		return newJSONRPCErrorResult(rpcReq.ID, err)
+	}
+
+	response, err := eth.NewJSONRPCResult(rpcReq.ID, result)
+	if err != nil {
+		return newJSONRPCErrorResult(rpcReq.ID, err)
+	}
+
+	return response
+}
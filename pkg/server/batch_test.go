@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dcb9/janus/pkg/eth"
+)
+
+func TestProcessBatch(t *testing.T) {
+	var calls int32
+	echoMethod := func(rpcReq *eth.JSONRPCRequest) *eth.JSONRPCResult {
+		atomic.AddInt32(&calls, 1)
+		// later requests finish first, the responses must keep the order of the requests anyway
+		if string(rpcReq.ID) == "1" {
+			time.Sleep(10 * time.Millisecond)
+		}
+		result, _ := eth.NewJSONRPCResult(rpcReq.ID, rpcReq.Method)
+		return result
+	}
+	opts := batchOptions{maxSize: 5, concurrency: 3}
+
+	tests := []struct {
+		in    string
+		want  string
+		calls int32
+	}{
+		{
+			in:    `[{"jsonrpc":"2.0","method":"a","id":1},{"jsonrpc":"2.0","method":"b","id":2},{"jsonrpc":"2.0","method":"c","id":3}]`,
+			want:  `[{"jsonrpc":"2.0","result":"a","id":1},{"jsonrpc":"2.0","result":"b","id":2},{"jsonrpc":"2.0","result":"c","id":3}]`,
+			calls: 3,
+		},
+		{
+			in:    `[{"jsonrpc":"2.0","method":"a","id":1},{"jsonrpc":"2.0","method":"notify"},1,{"jsonrpc":"2.0","id":4}]`,
+			want:  `[{"jsonrpc":"2.0","result":"a","id":1},{"jsonrpc":"2.0","error":{"code":-32600,"message":"json: cannot unmarshal number into Go value of type eth.JSONRPCRequest"},"id":null},{"jsonrpc":"2.0","error":{"code":-32600,"message":"method must be set"},"id":4}]`,
+			calls: 2,
+		},
+		{
+			in:    `[{"jsonrpc":"2.0","method":"notify"},{"jsonrpc":"2.0","method":"notify"}]`,
+			want:  `null`,
+			calls: 2,
+		},
+		{
+			in:   `[]`,
+			want: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch"},"id":null}`,
+		},
+		{
+			in:   `[1,2,3,4,5,6]`,
+			want: `{"jsonrpc":"2.0","error":{"code":-32600,"message":"batch of 6 requests exceeds the limit of 5"},"id":null}`,
+		},
+		{
+			in:   `[{"jsonrpc":"2.0","method":"a"`,
+			want: `{"jsonrpc":"2.0","error":{"code":-32700,"message":"unexpected end of JSON input"},"id":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		atomic.StoreInt32(&calls, 0)
+
+		got, err := json.Marshal(processBatch(opts, []byte(tt.in), echoMethod))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("in: %s, want: %s, got: %s", tt.in, tt.want, got)
+		}
+		if calls != tt.calls {
+			t.Errorf("in: %s, want: %d calls, got: %d calls", tt.in, tt.calls, calls)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	stdLog "log"
+	"net/http"
 
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/go-kit/kit/log/level"
@@ -20,27 +22,36 @@ func httpHandler(c echo.Context) error {
 	var rpcReq *eth.JSONRPCRequest
 	decoder := json.NewDecoder(c.Request().Body)
 	if err := decoder.Decode(&rpcReq); err != nil {
-		return err
+		return eth.NewParseError(err.Error())
+	}
+	if rpcReq == nil {
+		return eth.NewInvalidRequestError("method must be set")
 	}
 
+	// calls to qtumd are canceled when the client goes away
+	rpcReq = rpcReq.WithContext(c.Request().Context())
+	// errors, from here on, are replied with the id of the request
 	cc.rpcReq = rpcReq
+	if rpcReq.Method == "" {
+		return eth.NewInvalidRequestError("method must be set")
+	}
+	if isNotification(rpcReq) {
+		// notifications are processed, but they have no response, even if they fail
+		cc.transformRequest(rpcReq)
+		return c.NoContent(http.StatusNoContent)
+	}
 
 	level.Info(cc.logger).Log("msg", "proxy RPC", "method", rpcReq.Method)
 
+	if err := cc.admit(rpcReq); err != nil {
+		return err
+	}
+
 	// level.Debug(cc.logger).Log("msg", "before call transformer#Transform")
 	result, err := cc.transformer.Transform(rpcReq)
 	// level.Debug(cc.logger).Log("msg", "after call transformer#Transform")
 
 	if err != nil {
-		err1 := errors.Cause(err)
-		if err != err1 {
-			level.Error(cc.logger).Log("err", err.Error())
-			return cc.JSONRPCError(&eth.JSONRPCError{
-				Code:    100,
-				Message: err1.Error(),
-			})
-		}
-
 		return err
 	}
 
@@ -52,10 +63,7 @@ func errorHandler(err error, c echo.Context) {
 	cc, ok := myctx.(*myCtx)
 	if ok {
 		level.Error(cc.logger).Log("err", err.Error())
-		if err := cc.JSONRPCError(&eth.JSONRPCError{
-			Code:    100,
-			Message: err.Error(),
-		}); err != nil {
+		if err := cc.JSONRPCError(toJSONRPCError(err)); err != nil {
 			level.Error(cc.logger).Log("msg", "reply to client", "err", err.Error())
 		}
 		return
@@ -63,3 +71,11 @@ func errorHandler(err error, c echo.Context) {
 
 	stdLog.Println("errorHandler", err.Error())
 }
+
+// toJSONRPCError maps errors of the server onto JSON-RPC errors, errors of transformers already are JSON-RPC errors
+func toJSONRPCError(err error) *eth.JSONRPCError {
+	if httpErr, ok := err.(*echo.HTTPError); ok {
+		return eth.NewInvalidRequestError(fmt.Sprint(httpErr.Message))
+	}
+	return eth.ToJSONRPCError(err)
+}
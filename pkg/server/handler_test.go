@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/transformer"
+	"github.com/go-kit/kit/log"
+	"github.com/labstack/echo"
+)
+
+// echoProxy replies to echo with the params of the request
+type echoProxy struct {
+	calls int
+}
+
+func (p *echoProxy) Method() string {
+	return "echo"
+}
+
+func (p *echoProxy) Request(rpcReq *eth.JSONRPCRequest) (interface{}, error) {
+	p.calls++
+	return rpcReq.Params, nil
+}
+
+func TestNotification(t *testing.T) {
+	q, err := qtum.New(&qtum.Client{}, qtum.ChainRegTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := &echoProxy{}
+	tr, err := transformer.New(q, []transformer.ETHProxy{proxy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		in       string
+		wantCode int
+		wantBody string
+	}{
+		{`{"jsonrpc":"2.0","method":"echo","params":[1],"id":1}`, http.StatusOK, `{"jsonrpc":"2.0","result":[1],"id":1}`},
+		{`{"jsonrpc":"2.0","method":"echo","params":[1]}`, http.StatusNoContent, ``},
+	}
+
+	e := echo.New()
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(c.in))
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		cc := &myCtx{Context: ctx, logger: log.NewNopLogger(), transformer: tr, limiter: newLimiter(q)}
+		ctx.Set("myctx", cc)
+
+		if err := httpHandler(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(rec.Body.String()); rec.Code != c.wantCode || got != c.wantBody {
+			t.Errorf("in: %s, want: %d %s, got: %d %s", c.in, c.wantCode, c.wantBody, rec.Code, got)
+		}
+
+		// over a websocket, the notification gets no response at all
+		subscriptions := tr.NewSubscriptions(nil)
+		resp := handleWebsocketMessage(context.Background(), cc, subscriptions, []byte(c.in))
+		subscriptions.Close()
+		if (resp == nil) != (c.wantBody == "") {
+			t.Errorf("in: %s, want response: %t, got: %v", c.in, c.wantBody != "", resp)
+		}
+	}
+
+	// the notifications were still processed
+	if proxy.calls != 4 {
+		t.Errorf("want: %d calls, got: %d", 4, proxy.calls)
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/labstack/echo"
+)
+
+// readiness is the body of /readyz
+type readiness struct {
+	Ready                bool     `json:"ready"`
+	QtumReachable        bool     `json:"qtumReachable"`
+	InitialBlockDownload bool     `json:"initialBlockDownload"`
+	Blocks               int64    `json:"blocks"`
+	Headers              int64    `json:"headers"`
+	TipAgeSeconds        int64    `json:"tipAgeSeconds"`
+	WalletLocked         bool     `json:"walletLocked"`
+	Errors               []string `json:"errors,omitempty"`
+}
+
+// healthzHandler reports that the process is alive, it doesn't depend on qtumd
+func healthzHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyzHandler reports whether qtumd is reachable, synced and its wallet is unlocked, it replies 503 otherwise
+func (s *Server) readyzHandler(c echo.Context) error {
+	r := s.readiness(c.Request())
+
+	status := http.StatusOK
+	if !r.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, r)
+}
+
+func (s *Server) readiness(req *http.Request) *readiness {
+	ctx := req.Context()
+	r := &readiness{}
+
+	info, err := s.qtumRPCClient.GetBlockChainInfo(ctx)
+	if err != nil {
+		r.Errors = append(r.Errors, "qtumd is unreachable: "+err.Error())
+		return r
+	}
+	r.QtumReachable = true
+	r.InitialBlockDownload = info.InitialBlockDownload
+	r.Blocks = info.Blocks
+	r.Headers = info.Headers
+	if info.InitialBlockDownload {
+		r.Errors = append(r.Errors, "qtumd is in initial block download")
+	}
+
+	tip, err := s.qtumRPCClient.GetBlockHeader(ctx, info.Bestblockhash)
+	if err != nil {
+		r.Errors = append(r.Errors, "get tip: "+err.Error())
+	} else {
+		tipAge := time.Since(time.Unix(int64(tip.Time), 0))
+		r.TipAgeSeconds = int64(tipAge.Seconds())
+		// blocks of regtest are only generated on demand
+		if s.maxTipAge > 0 && tipAge > s.maxTipAge && s.qtumRPCClient.Chain() != qtum.ChainRegTest {
+			r.Errors = append(r.Errors, "the tip is older than "+s.maxTipAge.String())
+		}
+	}
+
+	wallet, err := s.qtumRPCClient.GetWalletInfo(ctx)
+	if err != nil {
+		r.Errors = append(r.Errors, "get wallet info: "+err.Error())
+	} else if wallet.IsLocked() {
+		r.WalletLocked = true
+		r.Errors = append(r.Errors, "the wallet is locked")
+	}
+
+	r.Ready = len(r.Errors) == 0
+	return r
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/transformer"
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+)
+
+// methodWeights are the costs of heavy methods in tokens of the rate limiter, other methods cost 1 token
+var methodWeights = map[string]float64{
+	"eth_getLogs":       10,
+	"eth_getFilterLogs": 10,
+}
+
+// feeHistoryBlocksPerToken is how many blocks of eth_feeHistory cost 1 token, it costs at least 1
+const feeHistoryBlocksPerToken = 10
+
+// limiter protects qtumd from clients which send too many or too expensive requests
+type limiter struct {
+	qtum *qtum.Qtum
+
+	// maximum size of the body of HTTP requests in bytes, 0 means unlimited
+	maxBodySize int64
+	// maximum number of blocks eth_getLogs searches, 0 means unlimited
+	maxLogBlockRange int64
+
+	// tokens per second each client gets, 0 disables rate limiting
+	rate float64
+	// the size of the token bucket of each client
+	burst float64
+
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(qtumClient *qtum.Qtum) *limiter {
+	return &limiter{
+		qtum:    qtumClient,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// take takes cost tokens from the bucket of client, it returns false if the bucket doesn't have enough tokens
+func (l *limiter) take(client string, cost float64, now time.Time) bool {
+	if l.rate <= 0 {
+		return true
+	}
+	// heavy methods are still allowed if they cost more than a full bucket
+	cost = math.Min(cost, l.burst)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < cost {
+		return false
+	}
+	b.tokens -= cost
+	return true
+}
+
+// sweep forgets the clients whose buckets are full again, so the buckets of past clients don't pile up
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, b := range l.buckets {
+		if now.Sub(b.last) > refill {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// admit returns an error if client exceeds its rate limit or rpcReq is too expensive
+func (l *limiter) admit(client string, rpcReq *eth.JSONRPCRequest) error {
+	if !l.take(client, methodCost(rpcReq), time.Now()) {
+		return eth.NewLimitExceededError(fmt.Sprintf("rate limit of %g requests per second exceeded", l.rate))
+	}
+
+	if rpcReq.Method == "eth_getLogs" {
+		return l.checkLogBlockRange(rpcReq)
+	}
+	return nil
+}
+
+// methodCost returns the tokens rpcReq costs, eth_feeHistory costs more the more blocks it returns
+func methodCost(rpcReq *eth.JSONRPCRequest) float64 {
+	if rpcReq.Method == "eth_feeHistory" {
+		var req eth.FeeHistoryRequest
+		if err := req.UnmarshalJSON(rpcReq.Params); err != nil {
+			// the proxy replies with the error
+			return 1
+		}
+		blocks, err := transformer.FeeHistoryBlocks(&req)
+		if err != nil {
+			return 1
+		}
+		return math.Max(1, math.Ceil(float64(blocks)/feeHistoryBlocksPerToken))
+	}
+
+	cost, ok := methodWeights[rpcReq.Method]
+	if !ok {
+		return 1
+	}
+	return cost
+}
+
+func (l *limiter) checkLogBlockRange(rpcReq *eth.JSONRPCRequest) error {
+	if l.maxLogBlockRange <= 0 {
+		return nil
+	}
+
+	var req eth.GetLogsRequest
+	if err := req.UnmarshalJSON(rpcReq.Params); err != nil {
+		// the proxy replies with the error
+		return nil
+	}
+	qtumreq, err := (&transformer.ProxyETHGetLogs{Qtum: l.qtum}).ToRequest(&req)
+	if err != nil {
+		return nil
+	}
+
+	from, to := qtumreq.FromBlock.Int64(), qtumreq.ToBlock.Int64()
+	if to < 0 {
+		// latest
+		blockCount, err := l.qtum.GetBlockCount(rpcReq.Context())
+		if err != nil {
+			return err
+		}
+		to = blockCount.Int64()
+	}
+
+	if to-from+1 > l.maxLogBlockRange {
+		return eth.NewLimitExceededError(fmt.Sprintf("eth_getLogs can search at most %d blocks, got %d", l.maxLogBlockRange, to-from+1))
+	}
+	return nil
+}
+
+// bodyLimitMiddleware rejects HTTP requests with a body larger than maxBodySize
+func (s *Server) bodyLimitMiddleware(h echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		max := s.limiter.maxBodySize
+		if max <= 0 || c.Request().Body == nil {
+			return h(c)
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(c.Request().Body, max+1))
+		if err != nil {
+			return err
+		}
+		if int64(len(body)) > max {
+			return c.JSON(http.StatusRequestEntityTooLarge, newJSONRPCErrorResult(nil, eth.NewLimitExceededError(fmt.Sprintf("request body exceeds %d bytes", max))))
+		}
+		c.Request().Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		return h(c)
+	}
+}
+
+// clientMiddleware tags the context of the request with its client, e.g. filters are counted per client
+func clientMiddleware(h echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cc, ok := c.Get("myctx").(*myCtx)
+		if !ok {
+			return errors.New("Could not find myctx")
+		}
+
+		c.SetRequest(c.Request().WithContext(eth.WithClient(c.Request().Context(), cc.client())))
+
+		return h(c)
+	}
+}
+
+// client identifies the client for rate limiting, by the name of its key if it is authenticated, otherwise by its IP
+func (c *myCtx) client() string {
+	if c.allowlist != nil && c.allowlist.name != "" {
+		return "key:" + c.allowlist.name
+	}
+	return "ip:" + c.RealIP()
+}
+
+// admit returns an error if the client cannot call rpcReq now
+func (c *myCtx) admit(rpcReq *eth.JSONRPCRequest) error {
+	if err := c.authorize(rpcReq.Method); err != nil {
+		return err
+	}
+	return c.limiter.admit(c.client(), rpcReq)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dcb9/janus/pkg/eth"
+)
+
+func TestLimiterTake(t *testing.T) {
+	l := newLimiter(nil)
+	l.rate, l.burst = 2, 4
+
+	now := time.Unix(1500000000, 0)
+	steps := []struct {
+		client  string
+		elapsed time.Duration
+		cost    float64
+		want    bool
+	}{
+		{"a", 0, 1, true},
+		{"a", 0, 10, false}, // costs a full bucket, 3 tokens are left
+		{"a", 0, 3, true},
+		{"a", 0, 1, false},
+		{"b", 0, 4, true}, // other clients have their own bucket
+		{"a", 500 * time.Millisecond, 1, true},
+		{"a", 0, 1, false},
+		{"a", time.Hour, 10, true}, // the bucket refilled, but it doesn't hold more than 4 tokens
+		{"a", 0, 1, false},
+	}
+
+	for i, step := range steps {
+		now = now.Add(step.elapsed)
+		if got := l.take(step.client, step.cost, now); got != step.want {
+			t.Errorf("step: %d, want: %t, got: %t", i, step.want, got)
+		}
+	}
+
+	if _, ok := l.buckets["b"]; ok {
+		t.Errorf("the full bucket of b was not swept")
+	}
+}
+
+func TestMethodCost(t *testing.T) {
+	cases := []struct {
+		method string
+		params string
+		want   float64
+	}{
+		{"eth_blockNumber", `[]`, 1},
+		{"eth_getLogs", `[{}]`, 10},
+		{"eth_feeHistory", `["0x4", "latest"]`, 1},
+		{"eth_feeHistory", `[45, "latest", [50]]`, 5},
+		// the blocks are capped
+		{"eth_feeHistory", `["0x400", "latest"]`, 10},
+		{"eth_feeHistory", `["bad", "latest"]`, 1},
+	}
+
+	for _, c := range cases {
+		rpcReq := &eth.JSONRPCRequest{Method: c.method, Params: json.RawMessage(c.params)}
+		if got := methodCost(rpcReq); got != c.want {
+			t.Errorf("in: %s %s, want: %g, got: %g", c.method, c.params, c.want, got)
+		}
+	}
+}
@@ -15,6 +15,10 @@ type myCtx struct {
 	rpcReq      *eth.JSONRPCRequest
 	logger      log.Logger
 	transformer *transformer.Transformer
+	batch       batchOptions
+	// the methods the client can call, nil if authentication is disabled
+	allowlist *allowlist
+	limiter   *limiter
 }
 
 func (c *myCtx) JSONRPCResult(result interface{}) error {
@@ -38,7 +42,8 @@ func (c *myCtx) JSONRPCError(err *eth.JSONRPCError) error {
 	}
 
 	if !c.Response().Committed {
-		return c.JSON(http.StatusInternalServerError, resp)
+		// JSON-RPC clients expect errors in the body of a successful HTTP response
+		return c.JSON(http.StatusOK, resp)
 	}
 
 	return nil
@@ -2,12 +2,11 @@ package server
 
 import (
 	"bytes"
-	"encoding/json"
 	"io/ioutil"
 	"net/http"
-	"net/http/httptest"
+	"time"
 
-	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/metrics"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/transformer"
 	"github.com/go-kit/kit/log"
@@ -24,6 +23,16 @@ type Server struct {
 	logger        log.Logger
 	debug         bool
 	echo          *echo.Echo
+
+	// /readyz fails if the tip of the chain is older, 0 disables the check
+	maxTipAge time.Duration
+
+	batch batchOptions
+
+	// nil if authentication is disabled
+	auth *AuthConfig
+
+	limiter *limiter
 }
 
 func New(
@@ -38,6 +47,9 @@ func New(
 		address:       addr,
 		qtumRPCClient: qtumRPCClient,
 		transformer:   transformer,
+		maxTipAge:     time.Hour,
+		batch:         batchOptions{maxSize: 100, concurrency: 10},
+		limiter:       newLimiter(qtumRPCClient),
 	}
 
 	var err error
@@ -69,6 +81,8 @@ func (s *Server) Start() error {
 				Context:     c,
 				logger:      s.logger,
 				transformer: s.transformer,
+				batch:       s.batch,
+				limiter:     s.limiter,
 			}
 
 			c.Set("myctx", cc)
@@ -77,12 +91,20 @@ func (s *Server) Start() error {
 		}
 	})
 
+	e.Use(s.authMiddleware)
+	e.Use(s.bodyLimitMiddleware)
+	e.Use(clientMiddleware)
+
 	// support batch requests
-	e.Use(batchRequestsMiddleware)
+	e.Use(s.batchRequestsMiddleware)
 
 	e.HTTPErrorHandler = errorHandler
 	e.HideBanner = true
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	e.GET("/healthz", healthzHandler)
+	e.GET("/readyz", s.readyzHandler)
 	e.POST("/*", httpHandler)
+	e.GET("/*", websocketHandler)
 
 	level.Warn(s.logger).Log("listen", s.address, "qtum_rpc", s.qtumRPCClient.URL, "msg", "proxy started")
 	return e.Start(s.address)
@@ -104,7 +126,90 @@ func SetDebug(debug bool) Option {
 	}
 }
 
-func batchRequestsMiddleware(h echo.HandlerFunc) echo.HandlerFunc {
+// SetMaxTipAge sets how old the tip of the chain can be before /readyz fails, 0 disables the check
+func SetMaxTipAge(maxTipAge time.Duration) Option {
+	return func(p *Server) error {
+		if maxTipAge < 0 {
+			return errors.New("max tip age cannot be negative")
+		}
+		p.maxTipAge = maxTipAge
+		return nil
+	}
+}
+
+// SetAuth requires clients to authenticate with a key or a JWT of config, nil disables authentication
+func SetAuth(config *AuthConfig) Option {
+	return func(p *Server) error {
+		if config != nil {
+			if err := config.validate(); err != nil {
+				return err
+			}
+		}
+		p.auth = config
+		return nil
+	}
+}
+
+// SetRateLimit limits each client to rate requests per second with bursts of up to burst requests, 0 disables rate limiting
+func SetRateLimit(rate float64, burst int) Option {
+	return func(p *Server) error {
+		if rate < 0 {
+			return errors.New("rate limit cannot be negative")
+		}
+		if rate > 0 && burst <= 0 {
+			return errors.New("rate limit burst must be positive")
+		}
+		p.limiter.rate = rate
+		p.limiter.burst = float64(burst)
+		return nil
+	}
+}
+
+// SetMaxBodySize sets the maximum size of the body of HTTP requests in bytes, 0 means unlimited
+func SetMaxBodySize(size int64) Option {
+	return func(p *Server) error {
+		if size < 0 {
+			return errors.New("max body size cannot be negative")
+		}
+		p.limiter.maxBodySize = size
+		return nil
+	}
+}
+
+// SetMaxLogBlockRange sets the maximum number of blocks eth_getLogs searches, 0 means unlimited
+func SetMaxLogBlockRange(blocks int64) Option {
+	return func(p *Server) error {
+		if blocks < 0 {
+			return errors.New("max log block range cannot be negative")
+		}
+		p.limiter.maxLogBlockRange = blocks
+		return nil
+	}
+}
+
+// SetMaxBatchSize sets the maximum number of requests in a batch, 0 means unlimited
+func SetMaxBatchSize(size int) Option {
+	return func(p *Server) error {
+		if size < 0 {
+			return errors.New("max batch size cannot be negative")
+		}
+		p.batch.maxSize = size
+		return nil
+	}
+}
+
+// SetBatchConcurrency sets how many requests of a batch are processed at once
+func SetBatchConcurrency(concurrency int) Option {
+	return func(p *Server) error {
+		if concurrency <= 0 {
+			return errors.New("batch concurrency must be positive")
+		}
+		p.batch.concurrency = concurrency
+		return nil
+	}
+}
+
+func (s *Server) batchRequestsMiddleware(h echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		myctx := c.Get("myctx")
 		cc, ok := myctx.(*myCtx)
@@ -117,61 +222,18 @@ func batchRequestsMiddleware(h echo.HandlerFunc) echo.HandlerFunc {
 		if c.Request().Body != nil { // Read
 			reqBody, _ = ioutil.ReadAll(c.Request().Body)
 		}
-		isBatchRequests := func(msg json.RawMessage) bool {
-			return msg[0] == '['
-		}
 		c.Request().Body = ioutil.NopCloser(bytes.NewBuffer(reqBody)) // Reset
 
 		if !isBatchRequests(reqBody) {
 			return h(c)
 		}
 
-		var rpcReqs []*eth.JSONRPCRequest
-		if err := c.Bind(&rpcReqs); err != nil {
-			return err
+		resp := processBatch(s.batch, reqBody, cc.transformRequest)
+		if resp == nil {
+			// the batch only has notifications
+			return c.NoContent(http.StatusNoContent)
 		}
 
-		results := make([]*eth.JSONRPCResult, 0, len(rpcReqs))
-
-		for _, req := range rpcReqs {
-			result, err := callHttpHandler(cc, req)
-			if err != nil {
-				return err
-			}
-
-			results = append(results, result)
-		}
-
-		return c.JSON(http.StatusOK, results)
-	}
-}
-
-func callHttpHandler(cc *myCtx, req *eth.JSONRPCRequest) (*eth.JSONRPCResult, error) {
-	reqBytes, err := json.Marshal(req)
-	if err != nil {
-		return nil, err
+		return c.JSON(http.StatusOK, resp)
 	}
-
-	httpreq := httptest.NewRequest(echo.POST, "/", ioutil.NopCloser(bytes.NewReader(reqBytes)))
-	httpreq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-	rec := httptest.NewRecorder()
-
-	newCtx := cc.Echo().NewContext(httpreq, rec)
-	myCtx := &myCtx{
-		Context:     newCtx,
-		logger:      cc.logger,
-		transformer: cc.transformer,
-	}
-	newCtx.Set("myctx", myCtx)
-
-	if err = httpHandler(myCtx); err != nil {
-		errorHandler(err, myCtx)
-	}
-
-	var result *eth.JSONRPCResult
-	if err = json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
-		return nil, err
-	}
-
-	return result, nil
 }
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/transformer"
+	"github.com/go-kit/kit/log/level"
+	"github.com/labstack/echo"
+	"github.com/pkg/errors"
+)
+
+// websocketNotifier pushes eth_subscription notifications to the websocket connection
+type websocketNotifier struct {
+	conn *wsConn
+}
+
+func (n *websocketNotifier) Notify(notification *eth.JSONRPCNotification) error {
+	return n.conn.WriteJSON(notification)
+}
+
+func websocketHandler(c echo.Context) error {
+	myctx := c.Get("myctx")
+	cc, ok := myctx.(*myCtx)
+	if !ok {
+		return errors.New("Could not find myctx")
+	}
+
+	if !isWebsocketUpgrade(c.Request()) {
+		return errors.New("Only websocket connections are served by GET requests")
+	}
+
+	conn, err := upgradeWebsocket(c.Response(), c.Request())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	subscriptions := cc.transformer.NewSubscriptions(&websocketNotifier{conn: conn})
+	defer subscriptions.Close()
+
+	// cancels the pending calls to qtumd when the connection is closed
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	level.Info(cc.logger).Log("msg", "websocket connected", "remote", c.RealIP())
+
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			if err != io.EOF {
+				level.Error(cc.logger).Log("msg", "websocket read", "err", err.Error())
+			}
+			return nil
+		}
+
+		resp := handleWebsocketMessage(ctx, cc, subscriptions, msg)
+		if resp == nil {
+			// notifications have no response
+			continue
+		}
+		if err := conn.WriteJSON(resp); err != nil {
+			level.Error(cc.logger).Log("msg", "websocket write", "err", err.Error())
+			return nil
+		}
+	}
+}
+
+// handleWebsocketMessage returns the response of a single or a batch request, it is nil if there is nothing to reply
+func handleWebsocketMessage(ctx context.Context, cc *myCtx, subscriptions *transformer.Subscriptions, msg []byte) interface{} {
+	if isBatchRequests(msg) {
+		return processBatch(cc.batch, msg, func(rpcReq *eth.JSONRPCRequest) *eth.JSONRPCResult {
+			return transformWebsocketRequest(ctx, cc, subscriptions, rpcReq)
+		})
+	}
+
+	var rpcReq *eth.JSONRPCRequest
+	if err := json.Unmarshal(msg, &rpcReq); err != nil {
+		return newJSONRPCErrorResult(nil, eth.NewParseError(err.Error()))
+	}
+
+	resp := transformWebsocketRequest(ctx, cc, subscriptions, rpcReq)
+	if isNotification(rpcReq) {
+		return nil
+	}
+	return resp
+}
+
+func transformWebsocketRequest(ctx context.Context, cc *myCtx, subscriptions *transformer.Subscriptions, rpcReq *eth.JSONRPCRequest) *eth.JSONRPCResult {
+	if rpcReq == nil {
+		return newJSONRPCErrorResult(nil, eth.NewInvalidRequestError("method must be set"))
+	}
+	if rpcReq.Method == "" {
+		return newJSONRPCErrorResult(rpcReq.ID, eth.NewInvalidRequestError("method must be set"))
+	}
+
+	level.Info(cc.logger).Log("msg", "proxy websocket RPC", "method", rpcReq.Method)
+
+	rpcReq = rpcReq.WithContext(ctx)
+	if err := cc.admit(rpcReq); err != nil {
+		return newJSONRPCErrorResult(rpcReq.ID, err)
+	}
+
+	result, err := subscriptions.Transform(rpcReq)
+	if err != nil {
+		level.Error(cc.logger).Log("err", err.Error())
+		return newJSONRPCErrorResult(rpcReq.ID, err)
+	}
+
+	response, err := eth.NewJSONRPCResult(rpcReq.ID, result)
+	if err != nil {
+		return newJSONRPCErrorResult(rpcReq.ID, err)
+	}
+
+	return response
+}
+
+func newJSONRPCErrorResult(id json.RawMessage, err error) *eth.JSONRPCResult {
+	return &eth.JSONRPCResult{
+		JSONRPC: eth.RPCVersion,
+		ID:      id,
+		Error:   toJSONRPCError(err),
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"testing"
+)
+
+func TestWebsocketAccept(t *testing.T) {
+	// example from RFC 6455 section 1.3
+	in, want := "dGhlIHNhbXBsZSBub25jZQ==", "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	if got := websocketAccept(in); got != want {
+		t.Errorf("in: %s, want: %s, got: %s", in, want, got)
+	}
+}
+
+func TestWebsocketReadMessage(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	conn := &wsConn{conn: server, rw: bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server))}
+	defer conn.Close()
+
+	mask := []byte{0x37, 0xfa, 0x21, 0x3d}
+	maskedFrame := func(header byte, payload string) []byte {
+		frame := append([]byte{header, 0x80 | byte(len(payload))}, mask...)
+		for i := range payload {
+			frame = append(frame, payload[i]^mask[i%4])
+		}
+		return frame
+	}
+
+	go func() {
+		// a text message split into two fragments
+		client.Write(maskedFrame(0x01, "Hel"))
+		client.Write(maskedFrame(0x80, "lo"))
+	}()
+
+	got, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello" {
+		t.Errorf("want: Hello, got: %s", got)
+	}
+}
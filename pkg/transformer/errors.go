@@ -0,0 +1,47 @@
+package transformer
+
+import (
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// toJSONRPCError maps the errors of proxies onto the JSON-RPC error codes
+func toJSONRPCError(err error) *eth.JSONRPCError {
+	switch cause := errors.Cause(err).(type) {
+	case *eth.JSONRPCError:
+		return cause
+	case *qtum.JSONRPCError:
+		switch cause.Code {
+		case qtum.ErrCodeTypeError, qtum.ErrCodeInvalidAddressOrKey, qtum.ErrCodeInvalidParameter, qtum.ErrCodeDeserializationError:
+			return eth.NewInvalidParamsError(cause.Message)
+		}
+		return eth.NewExecutionError(cause.Message)
+	}
+
+	if errors.Cause(err) == UnmarshalRequestErr {
+		return eth.NewInvalidParamsError(err.Error())
+	}
+
+	return eth.NewExecutionError(err.Error())
+}
+
+// isQtumdError returns whether err is an error returned by qtumd, e.g. because it does not run with an index,
+// rather than a failure to reach it
+func isQtumdError(err error) bool {
+	_, ok := errors.Cause(err).(*qtum.JSONRPCError)
+	return ok
+}
+
+// executionError returns the error of a contract call which was not successful, or nil
+func executionError(qtumresp *qtum.CallContractResponse) *eth.JSONRPCError {
+	switch qtumresp.ExecutionResult.Excepted {
+	case "None":
+		return nil
+	case "Revert":
+		return eth.NewRevertError(utils.AddHexPrefix(qtumresp.ExecutionResult.Output))
+	default:
+		return eth.NewExecutionError(qtumresp.ExecutionResult.Excepted)
+	}
+}
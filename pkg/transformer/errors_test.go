@@ -0,0 +1,28 @@
+package transformer
+
+import (
+	"testing"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/pkg/errors"
+)
+
+func TestToJSONRPCError(t *testing.T) {
+	cases := []struct {
+		in   error
+		want int
+	}{
+		{eth.NewRevertError("0x"), eth.ErrCodeRevert},
+		{errors.Wrap(&qtum.JSONRPCError{Code: qtum.ErrCodeInvalidAddressOrKey, Message: "Invalid address"}, "Client#do"), eth.ErrCodeInvalidParams},
+		{&qtum.JSONRPCError{Code: -26, Message: "mandatory-script-verify-flag-failed"}, eth.ErrCodeExecution},
+		{unmarshalRequest([]byte("{"), &eth.GetCodeRequest{}), eth.ErrCodeInvalidParams},
+		{errors.New("Invalid filter id"), eth.ErrCodeExecution},
+	}
+
+	for _, c := range cases {
+		if got := toJSONRPCError(c.in).Code; got != c.want {
+			t.Errorf("in: %s, want: %d, got: %d", c.in, c.want, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"context"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
@@ -15,23 +17,23 @@ func (p *ProxyETHAccounts) Method() string {
 	return "eth_accounts"
 }
 
-func (p *ProxyETHAccounts) Request(_ *eth.JSONRPCRequest) (interface{}, error) {
-	return p.request()
+func (p *ProxyETHAccounts) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
 }
 
-func (p *ProxyETHAccounts) request() (eth.AccountsResponse, error) {
+func (p *ProxyETHAccounts) request(ctx context.Context) (eth.AccountsResponse, error) {
 	// eth req -> qtum req
 	qtumreq := qtum.GetAddressesByAccountRequest("")
 
 	var qtumresp qtum.GetAddressesByAccountResponse
-	if err := p.Qtum.Request(qtum.MethodGetAddressesByAccount, &qtumreq, &qtumresp); err != nil {
+	if err := p.Qtum.Request(ctx, qtum.MethodGetAddressesByAccount, &qtumreq, &qtumresp); err != nil {
 		return nil, err
 	}
 
 	// qtum res -> eth res
 	var accounts eth.AccountsResponse
 	for _, base58Addr := range qtumresp {
-		addr, err := p.Base58AddressToHex(base58Addr)
+		addr, err := p.Base58ToHexAddress(base58Addr)
 
 		// discard addresses that cannot be converted to hex format (i.e. multisig, segwit)
 		if err != nil {
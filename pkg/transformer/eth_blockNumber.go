@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"context"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -15,12 +17,12 @@ func (p *ProxyETHBlockNumber) Method() string {
 	return "eth_blockNumber"
 }
 
-func (p *ProxyETHBlockNumber) Request(_ *eth.JSONRPCRequest) (interface{}, error) {
-	return p.request()
+func (p *ProxyETHBlockNumber) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
 }
 
-func (p *ProxyETHBlockNumber) request() (*eth.BlockNumberResponse, error) {
-	qtumresp, err := p.Qtum.GetBlockCount()
+func (p *ProxyETHBlockNumber) request(ctx context.Context) (*eth.BlockNumberResponse, error) {
+	qtumresp, err := p.Qtum.GetBlockCount(ctx)
 	if err != nil {
 		return nil, err
 	}
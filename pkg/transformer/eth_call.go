@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"context"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
@@ -9,6 +11,7 @@ import (
 // ProxyETHCall implements ETHProxy
 type ProxyETHCall struct {
 	*qtum.Qtum
+	gasPrices *GasPriceOracle
 }
 
 func (p *ProxyETHCall) Method() string {
@@ -21,27 +24,35 @@ func (p *ProxyETHCall) Request(rawreq *eth.JSONRPCRequest) (interface{}, error)
 		return nil, err
 	}
 
-	return p.request(&req)
+	return p.request(rawreq.Context(), &req)
 }
 
-func (p *ProxyETHCall) request(ethreq *eth.CallRequest) (*eth.CallResponse, error) {
+func (p *ProxyETHCall) request(ctx context.Context, ethreq *eth.CallRequest) (*eth.CallResponse, error) {
+	if err := checkStateBlock(ctx, p.Qtum, ethreq.BlockNumber); err != nil {
+		return nil, err
+	}
+
 	// eth req -> qtum req
-	qtumreq, err := p.ToRequest(ethreq)
+	qtumreq, err := p.ToRequest(ctx, ethreq)
 	if err != nil {
 		return nil, err
 	}
 
-	qtumresp, err := p.CallContract(qtumreq)
+	qtumresp, err := p.CallContract(ctx, qtumreq)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := executionError(qtumresp); err != nil {
+		return nil, err
+	}
+
 	// qtum res -> eth res
 	return p.ToResponse(qtumresp), nil
 }
 
-func (p *ProxyETHCall) ToRequest(ethreq *eth.CallRequest) (*qtum.CallContractRequest, error) {
-	gasLimit, _, err := EthGasToQtum(ethreq)
+func (p *ProxyETHCall) ToRequest(ctx context.Context, ethreq *eth.CallRequest) (*qtum.CallContractRequest, error) {
+	gasLimit, err := p.gasPrices.EthGasLimit(ctx, ethreq)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +60,7 @@ func (p *ProxyETHCall) ToRequest(ethreq *eth.CallRequest) (*qtum.CallContractReq
 	from := ethreq.From
 
 	if utils.IsEthHexAddress(from) {
-		from, err = p.FromHexAddress(from)
+		from, err = p.HexToBase58Address(from)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,25 @@
+package transformer
+
+import (
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHChainID implements ETHProxy
+type ProxyETHChainID struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHChainID) Method() string {
+	return "eth_chainId"
+}
+
+func (p *ProxyETHChainID) Request(_ *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request()
+}
+
+func (p *ProxyETHChainID) request() (*eth.ChainIDResponse, error) {
+	resp := eth.ChainIDResponse(hexutil.EncodeUint64(uint64(p.ChainID())))
+	return &resp, nil
+}
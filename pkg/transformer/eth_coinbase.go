@@ -0,0 +1,35 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/pkg/errors"
+)
+
+// ProxyETHCoinbase implements ETHProxy
+type ProxyETHCoinbase struct {
+	*ProxyETHAccounts
+}
+
+func (p *ProxyETHCoinbase) Method() string {
+	return "eth_coinbase"
+}
+
+func (p *ProxyETHCoinbase) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+// request returns the first account of the wallet of qtumd, the one eth_accounts returns first
+func (p *ProxyETHCoinbase) request(ctx context.Context) (*eth.CoinbaseResponse, error) {
+	accounts, err := p.ProxyETHAccounts.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, errors.New("the wallet of qtumd has no accounts")
+	}
+
+	resp := eth.CoinbaseResponse(accounts[0])
+	return &resp, nil
+}
@@ -21,17 +21,25 @@ func (p *ProxyETHEstimateGas) Request(rawreq *eth.JSONRPCRequest) (interface{},
 		return nil, err
 	}
 
+	if err := checkStateBlock(rawreq.Context(), p.Qtum, ethreq.BlockNumber); err != nil {
+		return nil, err
+	}
+
 	// eth req -> qtum req
-	qtumreq, err := p.ToRequest(&ethreq)
+	qtumreq, err := p.ToRequest(rawreq.Context(), &ethreq)
 	if err != nil {
 		return nil, err
 	}
 
-	qtumresp, err := p.CallContract(qtumreq)
+	qtumresp, err := p.CallContract(rawreq.Context(), qtumreq)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := executionError(qtumresp); err != nil {
+		return nil, err
+	}
+
 	return p.toResp(qtumresp)
 }
 
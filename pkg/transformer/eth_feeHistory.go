@@ -0,0 +1,140 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// maxFeeHistoryBlocks is the maximum number of blocks eth_feeHistory returns, each block costs several calls to qtumd
+const maxFeeHistoryBlocks = 100
+
+// ProxyETHFeeHistory implements ETHProxy
+type ProxyETHFeeHistory struct {
+	*GasPriceOracle
+}
+
+func (p *ProxyETHFeeHistory) Method() string {
+	return "eth_feeHistory"
+}
+
+func (p *ProxyETHFeeHistory) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.FeeHistoryRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), &req)
+}
+
+// request returns the fees of the blockCount blocks up to newestBlock, qtum has no base fee, so the base fees are 0
+// and the rewards are the gas prices paid by the contract transactions
+func (p *ProxyETHFeeHistory) request(ctx context.Context, req *eth.FeeHistoryRequest) (*eth.FeeHistoryResponse, error) {
+	blockCount, err := FeeHistoryBlocks(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, percentile := range req.RewardPercentiles {
+		if percentile < 0 || percentile > 100 || (i > 0 && percentile < req.RewardPercentiles[i-1]) {
+			return nil, eth.NewInvalidParamsError(fmt.Sprintf("invalid reward percentile: %g", percentile))
+		}
+	}
+
+	tip, err := p.GetBlockCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newest := tip.Int64()
+	if isBlockTag(req.NewestBlock, "earliest") {
+		newest = 0
+	} else if !isBlockTag(req.NewestBlock, "latest", "pending") {
+		number, err := getQtumBlockNumber(req.NewestBlock, -1)
+		if err != nil {
+			return nil, eth.NewInvalidParamsError(err.Error())
+		}
+		if number.Cmp(tip.Int) > 0 {
+			return nil, eth.NewInvalidParamsError(fmt.Sprintf("block %s not found, the latest block is %s", number, tip.Int))
+		}
+		if number.Sign() >= 0 {
+			newest = number.Int64()
+		}
+	}
+
+	if blockCount > uint64(newest)+1 {
+		blockCount = uint64(newest) + 1
+	}
+	oldest := newest + 1 - int64(blockCount)
+
+	resp := &eth.FeeHistoryResponse{
+		OldestBlock:   hexutil.EncodeUint64(uint64(oldest)),
+		BaseFeePerGas: make([]string, 0, blockCount+1),
+		GasUsedRatio:  make([]float64, 0, blockCount),
+	}
+	if len(req.RewardPercentiles) > 0 {
+		resp.Reward = make([][]string, 0, blockCount)
+	}
+
+	for n := oldest; n <= newest; n++ {
+		outputs, err := p.blockGas(ctx, big.NewInt(n), true)
+		if err != nil {
+			return nil, err
+		}
+		gasLimit, err := p.BlockGasLimit(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+
+		var gasUsed uint64
+		for _, out := range outputs {
+			gasUsed += out.gasUsed
+		}
+
+		resp.BaseFeePerGas = append(resp.BaseFeePerGas, "0x0")
+		resp.GasUsedRatio = append(resp.GasUsedRatio, float64(gasUsed)/float64(gasLimit))
+		if len(req.RewardPercentiles) > 0 {
+			resp.Reward = append(resp.Reward, p.rewards(outputs, gasUsed, req.RewardPercentiles))
+		}
+	}
+	// the base fee of the next block
+	resp.BaseFeePerGas = append(resp.BaseFeePerGas, "0x0")
+
+	return resp, nil
+}
+
+// rewards returns the gas prices at the percentiles of the gas used by the outputs, which are sorted by gas price
+func (p *ProxyETHFeeHistory) rewards(outputs []contractGas, gasUsed uint64, percentiles []float64) []string {
+	rewards := make([]string, len(percentiles))
+	if len(outputs) == 0 {
+		for i := range rewards {
+			rewards[i] = "0x0"
+		}
+		return rewards
+	}
+
+	i, sum := 0, outputs[0].gasUsed
+	for j, percentile := range percentiles {
+		threshold := uint64(float64(gasUsed) * percentile / 100)
+		for sum < threshold && i < len(outputs)-1 {
+			i++
+			sum += outputs[i].gasUsed
+		}
+		rewards[j] = hexutil.EncodeBig(p.SatoshisToWei(outputs[i].price))
+	}
+	return rewards
+}
+
+// FeeHistoryBlocks returns how many blocks eth_feeHistory returns for req, at most maxFeeHistoryBlocks
+func FeeHistoryBlocks(req *eth.FeeHistoryRequest) (uint64, error) {
+	blockCount, err := decodeQuantity(req.BlockCount)
+	if err != nil {
+		return 0, eth.NewInvalidParamsError(fmt.Sprintf("invalid blockCount: %s", err))
+	}
+	if blockCount > maxFeeHistoryBlocks {
+		blockCount = maxFeeHistoryBlocks
+	}
+	return blockCount, nil
+}
@@ -0,0 +1,31 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHGasPrice implements ETHProxy
+type ProxyETHGasPrice struct {
+	*GasPriceOracle
+}
+
+func (p *ProxyETHGasPrice) Method() string {
+	return "eth_gasPrice"
+}
+
+func (p *ProxyETHGasPrice) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+func (p *ProxyETHGasPrice) request(ctx context.Context) (*eth.GasPriceResponse, error) {
+	price, err := p.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.GasPriceResponse(hexutil.EncodeBig(p.SatoshisToWei(price)))
+	return &resp, nil
+}
@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"math/big"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
@@ -22,38 +24,42 @@ func (p *ProxyETHGetBalance) Request(rawreq *eth.JSONRPCRequest) (interface{}, e
 		return nil, err
 	}
 
+	ctx := rawreq.Context()
+	if err := checkStateBlock(ctx, p.Qtum, req.Block); err != nil {
+		return nil, err
+	}
+
 	addr := utils.RemoveHexPrefix(req.Address)
 	{
 		// is address a contract or an account?
 		qtumreq := qtum.GetAccountInfoRequest(addr)
-		qtumresp, err := p.GetAccountInfo(&qtumreq)
+		qtumresp, err := p.GetAccountInfo(ctx, &qtumreq)
 
 		// the address is a contract
 		if err == nil {
 			// the unit of the balance Satoshi
-			return hexutil.EncodeUint64(uint64(qtumresp.Balance)), nil
+			return hexutil.EncodeBig(p.SatoshisToWei(big.NewInt(int64(qtumresp.Balance)))), nil
 		}
 	}
 
 	{
 		// try account
-		base58Addr, err := p.FromHexAddress(addr)
+		base58Addr, err := p.HexToBase58Address(addr)
 		if err != nil {
 			return nil, err
 		}
 
 		qtumreq := qtum.NewListUnspentRequest(base58Addr)
-		qtumresp, err := p.ListUnspent(qtumreq)
+		qtumresp, err := p.ListUnspent(ctx, qtumreq)
 		if err != nil {
 			return nil, err
 		}
 
-		balance := float64(0)
+		balance := new(big.Int)
 		for _, utxo := range *qtumresp {
-			balance += utxo.Amount
+			balance.Add(balance, utxo.Amount.Satoshis())
 		}
 
-		// 1 QTUM = 10 ^ 8 Satoshi
-		return hexutil.EncodeUint64(uint64(balance * 1e8)), nil
+		return hexutil.EncodeBig(p.SatoshisToWei(balance)), nil
 	}
 }
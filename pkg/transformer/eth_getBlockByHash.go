@@ -0,0 +1,30 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/utils"
+)
+
+// ProxyETHGetBlockByHash implements ETHProxy
+type ProxyETHGetBlockByHash struct {
+	*ProxyETHGetBlockByNumber
+}
+
+func (p *ProxyETHGetBlockByHash) Method() string {
+	return "eth_getBlockByHash"
+}
+
+func (p *ProxyETHGetBlockByHash) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.GetBlockByHashRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), &req)
+}
+
+func (p *ProxyETHGetBlockByHash) request(ctx context.Context, req *eth.GetBlockByHashRequest) (*eth.GetBlockByNumberResponse, error) {
+	return p.blockByHash(ctx, utils.RemoveHexPrefix(req.BlockHash), req.FullTransaction)
+}
@@ -1,15 +1,23 @@
 package transformer
 
 import (
+	"context"
+	"log"
+	"strings"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
+// zeroAddress is the miner of blocks whose reward is not paid to an address
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
 // ProxyETHGetBlockByNumber implements ETHProxy
 type ProxyETHGetBlockByNumber struct {
 	*qtum.Qtum
+	gasPrices *GasPriceOracle
 }
 
 func (p *ProxyETHGetBlockByNumber) Method() string {
@@ -22,32 +30,58 @@ func (p *ProxyETHGetBlockByNumber) Request(rawreq *eth.JSONRPCRequest) (interfac
 		return nil, err
 	}
 
-	return p.request(&req)
+	return p.request(rawreq.Context(), &req)
 }
-func (p *ProxyETHGetBlockByNumber) request(req *eth.GetBlockByNumberRequest) (*eth.GetBlockByNumberResponse, error) {
+func (p *ProxyETHGetBlockByNumber) request(ctx context.Context, req *eth.GetBlockByNumberRequest) (*eth.GetBlockByNumberResponse, error) {
 	blockNum, err := getQtumBlockNumber(req.BlockNumber, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	blockHash, err := p.GetBlockHash(blockNum)
+	blockHash, err := p.GetBlockHash(ctx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.blockByHash(ctx, string(blockHash), req.FullTransaction)
+}
+
+func (p *ProxyETHGetBlockByNumber) blockByHash(ctx context.Context, blockHash string, fullTx bool) (*eth.GetBlockByNumberResponse, error) {
+	blockHeaderResp, err := p.GetBlockHeader(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	blockResp, err := p.GetBlockWithTransactions(ctx, blockHash)
 	if err != nil {
 		return nil, err
 	}
 
-	blockHeaderResp, err := p.GetBlockHeader(string(blockHash))
+	var txs []interface{}
+	if fullTx {
+		if txs, err = p.fullTransactions(ctx, blockResp); err != nil {
+			return nil, err
+		}
+	} else {
+		txs = make([]interface{}, 0, len(blockResp.Tx))
+		for _, tx := range blockResp.Tx {
+			txs = append(txs, utils.AddHexPrefix(tx.Txid))
+		}
+	}
+
+	bloom, gasUsed, err := p.blockReceipts(ctx, blockResp)
 	if err != nil {
 		return nil, err
 	}
 
-	blockResp, err := p.GetBlock(string(blockHash))
+	miner, err := p.blockMiner(ctx, blockResp)
 	if err != nil {
 		return nil, err
 	}
 
-	txs := make([]string, 0, len(blockResp.Tx))
-	for _, tx := range blockResp.Tx {
-		txs = append(txs, utils.AddHexPrefix(tx))
+	gasLimit, err := p.gasPrices.BlockGasLimit(ctx, int64(blockHeaderResp.Height))
+	if err != nil {
+		return nil, err
 	}
 
 	return &eth.GetBlockByNumberResponse{
@@ -58,15 +92,105 @@ func (p *ProxyETHGetBlockByNumber) request(req *eth.GetBlockByNumberRequest) (*e
 		Difficulty:       hexutil.EncodeUint64(uint64(blockHeaderResp.Difficulty)),
 		Timestamp:        hexutil.EncodeUint64(blockHeaderResp.Time),
 		StateRoot:        utils.AddHexPrefix(blockHeaderResp.HashStateRoot),
+		LogsBloom:        bloom.Hex(),
 		Size:             hexutil.EncodeUint64(uint64(blockResp.Size)),
 		Transactions:     txs,
 		TransactionsRoot: utils.AddHexPrefix(blockResp.Merkleroot),
+		Miner:            miner,
+		GasLimit:         hexutil.EncodeUint64(gasLimit),
+		GasUsed:          hexutil.EncodeUint64(gasUsed),
 
 		ExtraData:       "0x0",
-		Miner:           "0x0000000000000000000000000000000000000000",
 		TotalDifficulty: "0x0",
-		GasLimit:        "0x0",
-		GasUsed:         "0x0",
 		Uncles:          []string{},
 	}, nil
 }
+
+// fullTransactions converts the transactions of the block the same way as eth_getTransactionByHash
+func (p *ProxyETHGetBlockByNumber) fullTransactions(ctx context.Context, block *qtum.GetBlockWithTransactionsResponse) ([]interface{}, error) {
+	txByHash := &ProxyETHGetTransactionByHash{Qtum: p.Qtum}
+	nonces := &Nonces{Qtum: p.Qtum}
+
+	txs := make([]interface{}, 0, len(block.Tx))
+	for index, tx := range block.Tx {
+		ethTx, err := txByHash.toEthTransaction(ctx, tx, block.Hash, nonces)
+		if err != nil {
+			return nil, err
+		}
+
+		ethTx.BlockNumber = hexutil.EncodeUint64(uint64(block.Height))
+		ethTx.TransactionIndex = hexutil.EncodeUint64(uint64(index))
+
+		txs = append(txs, ethTx)
+	}
+
+	return txs, nil
+}
+
+// blockReceipts returns the Or of the blooms and the sum of the gas used of the block's contract receipts,
+// an empty bloom and no gas used if qtumd has no receipts, i.e. it runs without -logevents
+func (p *ProxyETHGetBlockByNumber) blockReceipts(ctx context.Context, block *qtum.GetBlockWithTransactionsResponse) (*eth.Bloom, uint64, error) {
+	var bloom eth.Bloom
+	var gasUsed uint64
+	for _, tx := range block.Tx {
+		if !isContractTx(tx) {
+			continue
+		}
+
+		receipts, err := p.GetTransactionReceipts(ctx, tx.Txid)
+		if isQtumdError(err) {
+			log.Println("no receipts of block ", block.Hash, ", is qtumd running with -logevents? err: ", err)
+			return &eth.Bloom{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for i := range receipts {
+			receiptBloom, err := eth.LogsBloom(getEthLogs(&receipts[i]))
+			if err != nil {
+				return nil, 0, err
+			}
+			bloom.Or(receiptBloom)
+			gasUsed += receipts[i].GasUsed
+		}
+	}
+
+	return &bloom, gasUsed, nil
+}
+
+// blockMiner returns the hex address rewarded by the block, i.e. the staker of the coinstake transaction
+// of a proof-of-stake block, or the first address paid by the coinbase transaction of a proof-of-work block.
+// It is the zero address if the reward is not paid to a P2PKH address, e.g. to a P2SH or a bech32 one.
+func (p *ProxyETHGetBlockByNumber) blockMiner(ctx context.Context, block *qtum.GetBlockWithTransactionsResponse) (string, error) {
+	rewardTx := 0
+	if strings.Contains(block.Flags, "proof-of-stake") {
+		rewardTx = 1
+	}
+	if len(block.Tx) <= rewardTx {
+		return zeroAddress, nil
+	}
+
+	for _, out := range block.Tx[rewardTx].Vout {
+		if len(out.ScriptPubKey.Addresses) == 0 {
+			continue
+		}
+
+		hexAddress, err := p.Base58ToHexAddress(out.ScriptPubKey.Addresses[0])
+		if err != nil {
+			return zeroAddress, nil
+		}
+		return utils.AddHexPrefix(hexAddress), nil
+	}
+
+	return zeroAddress, nil
+}
+
+func isContractTx(tx *qtum.DecodedRawTransactionResponse) bool {
+	for _, out := range tx.Vout {
+		if out.ScriptPubKey.Type == "call" || out.ScriptPubKey.Type == "create" {
+			return true
+		}
+	}
+	return false
+}
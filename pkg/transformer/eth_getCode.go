@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"context"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
@@ -21,13 +23,17 @@ func (p *ProxyETHGetCode) Request(rawreq *eth.JSONRPCRequest) (interface{}, erro
 		return nil, err
 	}
 
-	return p.request(&req)
+	return p.request(rawreq.Context(), &req)
 }
 
-func (p *ProxyETHGetCode) request(ethreq *eth.GetCodeRequest) (eth.GetCodeResponse, error) {
+func (p *ProxyETHGetCode) request(ctx context.Context, ethreq *eth.GetCodeRequest) (eth.GetCodeResponse, error) {
+	if err := checkStateBlock(ctx, p.Qtum, ethreq.BlockNumber); err != nil {
+		return "", err
+	}
+
 	qtumreq := qtum.GetAccountInfoRequest(utils.RemoveHexPrefix(ethreq.Address))
 
-	qtumresp, err := p.GetAccountInfo(&qtumreq)
+	qtumresp, err := p.GetAccountInfo(ctx, &qtumreq)
 	if err != nil {
 		return "", err
 	}
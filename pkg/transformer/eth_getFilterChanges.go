@@ -1,6 +1,7 @@
 package transformer
 
 import (
+	"context"
 	"encoding/json"
 	"math/big"
 
@@ -39,20 +40,20 @@ func (p *ProxyETHGetFilterChanges) Request(rawreq *eth.JSONRPCRequest) (interfac
 	}
 	filter := _filter.(*eth.Filter)
 
+	ctx := rawreq.Context()
 	switch filter.Type {
 	case eth.NewFilterTy:
-		return p.requestFilter(filter)
+		return p.requestFilter(ctx, filter)
 	case eth.NewBlockFilterTy:
-		return p.requestBlockFilter(filter)
+		return p.requestBlockFilter(ctx, filter)
 	case eth.NewPendingTransactionFilterTy:
-		fallthrough
+		return p.requestPendingTransactionFilter(ctx, filter)
 	default:
-
 		return nil, errors.New("Unknown filter type")
 	}
 }
 
-func (p *ProxyETHGetFilterChanges) requestBlockFilter(filter *eth.Filter) (qtumresp eth.GetFilterChangesResponse, err error) {
+func (p *ProxyETHGetFilterChanges) requestBlockFilter(ctx context.Context, filter *eth.Filter) (qtumresp eth.GetFilterChangesResponse, err error) {
 	qtumresp = make(eth.GetFilterChangesResponse, 0)
 
 	_lastBlockNumber, ok := filter.Data.Load("lastBlockNumber")
@@ -61,31 +62,71 @@ func (p *ProxyETHGetFilterChanges) requestBlockFilter(filter *eth.Filter) (qtumr
 	}
 	lastBlockNumber := _lastBlockNumber.(uint64)
 
-	blockCountBigInt, err := p.GetBlockCount()
+	blockCountBigInt, err := p.GetBlockCount(ctx)
 	if err != nil {
 		return qtumresp, err
 	}
 	blockCount := blockCountBigInt.Uint64()
 
+	// after a reorg the hashes of the blocks which replace the orphaned ones are delivered again
+	lastBlockNumber, _, err = p.rewind(ctx, filter, lastBlockNumber, blockCount)
+	if err != nil {
+		return qtumresp, err
+	}
+	if lastBlockNumber >= blockCount {
+		// no new blocks, or the node is behind
+		return qtumresp, nil
+	}
+
 	differ := blockCount - lastBlockNumber
 
+	blocks := filterDeliveredBlocks(filter)
 	hashes := make(eth.GetFilterChangesResponse, differ)
 	for i, _ := range hashes {
-		blockNumber := new(big.Int).SetUint64(lastBlockNumber + uint64(i) + 1)
+		number := lastBlockNumber + uint64(i) + 1
 
-		resp, err := p.GetBlockHash(blockNumber)
+		resp, err := p.GetBlockHashUncached(ctx, new(big.Int).SetUint64(number))
 		if err != nil {
 			return qtumresp, err
 		}
 
 		hashes[i] = utils.AddHexPrefix(string(resp))
+		blocks = append(blocks, deliveredBlock{number: number, hash: string(resp)})
 	}
 
 	qtumresp = hashes
+	storeDeliveredBlocks(filter, blocks)
 	filter.Data.Store("lastBlockNumber", blockCount)
 	return
 }
-func (p *ProxyETHGetFilterChanges) requestFilter(filter *eth.Filter) (qtumresp eth.GetFilterChangesResponse, err error) {
+
+// requestPendingTransactionFilter returns the hashes of the transactions which entered the mempool since the last poll
+func (p *ProxyETHGetFilterChanges) requestPendingTransactionFilter(ctx context.Context, filter *eth.Filter) (eth.GetFilterChangesResponse, error) {
+	_seen, ok := filter.Data.Load("mempool")
+	if !ok {
+		return nil, errors.New("Could not get mempool")
+	}
+	seen := _seen.(map[string]bool)
+
+	mempool, err := p.GetRawMempool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(eth.GetFilterChangesResponse, 0)
+	current := make(map[string]bool, len(mempool))
+	for _, txid := range mempool {
+		current[txid] = true
+		if !seen[txid] {
+			hashes = append(hashes, utils.AddHexPrefix(txid))
+		}
+	}
+
+	filter.Data.Store("mempool", current)
+	return hashes, nil
+}
+
+func (p *ProxyETHGetFilterChanges) requestFilter(ctx context.Context, filter *eth.Filter) (qtumresp eth.GetFilterChangesResponse, err error) {
 	qtumresp = make(eth.GetFilterChangesResponse, 0)
 
 	_lastBlockNumber, ok := filter.Data.Load("lastBlockNumber")
@@ -94,34 +135,62 @@ func (p *ProxyETHGetFilterChanges) requestFilter(filter *eth.Filter) (qtumresp e
 	}
 	lastBlockNumber := _lastBlockNumber.(uint64)
 
-	blockCountBigInt, err := p.GetBlockCount()
+	blockCountBigInt, err := p.GetBlockCount(ctx)
 	if err != nil {
 		return qtumresp, err
 	}
 	blockCount := blockCountBigInt.Uint64()
 
-	differ := blockCount - lastBlockNumber
+	// after a reorg the logs of the orphaned blocks are delivered again as removed,
+	// followed by the logs of the blocks which replace them
+	lastBlockNumber, orphaned, err := p.rewind(ctx, filter, lastBlockNumber, blockCount)
+	if err != nil {
+		return nil, err
+	}
+	qtumresp = append(qtumresp, removedLogs(orphaned)...)
+
+	if lastBlockNumber >= blockCount {
+		// no new blocks, or the node is behind
+		return qtumresp, nil
+	}
+
+	// a filter far behind catches up over several polls
+	to := blockCount
+	if max := uint64(p.filter.MaxLogBlockRange()); max > 0 && to-lastBlockNumber > max {
+		to = lastBlockNumber + max
+	}
 
-	if differ == 0 {
-		return eth.GetFilterChangesResponse{}, nil
+	searchLogsReq, err := p.toSearchLogsReq(filter, big.NewInt(int64(lastBlockNumber+1)), big.NewInt(int64(to)))
+	if err != nil {
+		return nil, err
 	}
 
-	searchLogsReq, err := p.toSearchLogsReq(filter, big.NewInt(int64(lastBlockNumber+1)), big.NewInt(int64(blockCount)))
+	logs, err := p.doSearchLogs(ctx, searchLogsReq, filterTopics(filter))
 	if err != nil {
 		return nil, err
 	}
 
-	return p.doSearchLogs(searchLogsReq)
+	delivered := make([]eth.Log, len(logs))
+	for i, log := range logs {
+		delivered[i] = log.(eth.Log)
+	}
+	if err = p.deliverBlocks(ctx, filter, lastBlockNumber, to, delivered); err != nil {
+		return nil, err
+	}
+
+	qtumresp = append(qtumresp, logs...)
+	filter.Data.Store("lastBlockNumber", to)
+	return
 }
 
-func (p *ProxyETHGetFilterChanges) doSearchLogs(req *qtum.SearchLogsRequest) (eth.GetFilterChangesResponse, error) {
-	resp, err := p.SearchLogs(req)
+func (p *ProxyETHGetFilterChanges) doSearchLogs(ctx context.Context, req *qtum.SearchLogsRequest, topics topicFilter) (eth.GetFilterChangesResponse, error) {
+	resp, err := p.SearchLogs(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
 	receiptToResult := func(receipt *qtum.TransactionReceiptStruct) []interface{} {
-		logs := getEthLogs(receipt)
+		logs := filterLogs(getEthLogs(receipt), req.Addresses, topics)
 		res := make([]interface{}, len(logs))
 		for i, _ := range res {
 			res[i] = logs[i]
@@ -164,10 +233,15 @@ func (p *ProxyETHGetFilterChanges) toSearchLogsReq(filter *eth.Filter, from, to
 		ToBlock:   to,
 	}
 
-	topics, ok := filter.Data.Load("topics")
-	if ok {
-		qtumreq.Topics = topics.([]interface{})
-	}
+	qtumreq.Topics = filterTopics(filter).searchLogsTopics()
 
 	return qtumreq, nil
 }
+
+func filterTopics(filter *eth.Filter) topicFilter {
+	topics, ok := filter.Data.Load("topics")
+	if !ok {
+		return nil
+	}
+	return topics.(topicFilter)
+}
@@ -1,8 +1,10 @@
 package transformer
 
 import (
+	"context"
 	"encoding/json"
 	"math/big"
+	"strings"
 
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
@@ -26,20 +28,22 @@ func (p *ProxyETHGetLogs) Request(rawreq *eth.JSONRPCRequest) (interface{}, erro
 		return nil, err
 	}
 
-	if len(req.Topics) != 0 {
-		return nil, errors.New("topics is not supported yet")
+	topics, err := newTopicFilter(req.Topics)
+	if err != nil {
+		return nil, err
 	}
 
 	qtumreq, err := p.ToRequest(&req)
 	if err != nil {
 		return nil, err
 	}
+	qtumreq.Topics = topics.searchLogsTopics()
 
-	return p.request(qtumreq)
+	return p.request(rawreq.Context(), qtumreq, topics)
 }
 
-func (p *ProxyETHGetLogs) request(req *qtum.SearchLogsRequest) (*eth.GetLogsResponse, error) {
-	receipts, err := p.SearchLogs(req)
+func (p *ProxyETHGetLogs) request(ctx context.Context, req *qtum.SearchLogsRequest, topics topicFilter) (*eth.GetLogsResponse, error) {
+	receipts, err := p.SearchLogs(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +51,7 @@ func (p *ProxyETHGetLogs) request(req *qtum.SearchLogsRequest) (*eth.GetLogsResp
 	logs := make([]eth.Log, 0)
 	for _, receipt := range receipts {
 		r := qtum.TransactionReceiptStruct(receipt)
-		logs = append(logs, getEthLogs(&r)...)
+		logs = append(logs, filterLogs(getEthLogs(&r), req.Addresses, topics)...)
 	}
 
 	resp := eth.GetLogsResponse(logs)
@@ -143,3 +147,117 @@ func getEthLogs(receipt *qtum.TransactionReceiptStruct) []eth.Log {
 	}
 	return logs
 }
+
+// topicFilter is the Ethereum topic syntax, the log topic at position i must be one of topicFilter[i],
+// an empty position matches any topic
+type topicFilter [][]string
+
+func newTopicFilter(ethtopics []interface{}) (topicFilter, error) {
+	if len(ethtopics) > 4 {
+		return nil, errors.New("at most 4 topics are allowed")
+	}
+
+	filter := make(topicFilter, len(ethtopics))
+	for i, topic := range ethtopics {
+		switch topic := topic.(type) {
+		case nil:
+		case string:
+			filter[i] = []string{normalizeHex(topic)}
+		case []interface{}:
+			values, err := orTopicValues(topic)
+			if err != nil {
+				return nil, err
+			}
+			filter[i] = values
+		default:
+			return nil, errors.Errorf("invalid topic %v", topic)
+		}
+	}
+
+	return filter, nil
+}
+
+func orTopicValues(topics []interface{}) ([]string, error) {
+	values := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		switch topic := topic.(type) {
+		case nil:
+			// null in an OR-array matches anything
+			return nil, nil
+		case string:
+			values = append(values, normalizeHex(topic))
+		default:
+			return nil, errors.Errorf("invalid topic %v", topic)
+		}
+	}
+	return values, nil
+}
+
+// searchLogsTopics returns the topics passed down to qtumd's searchlogs,
+// which only matches single values, OR-arrays are left to match
+func (f topicFilter) searchLogsTopics() []interface{} {
+	topics := make([]interface{}, len(f))
+	last := -1
+	for i, values := range f {
+		if len(values) == 1 {
+			topics[i] = values[0]
+			last = i
+		} else {
+			topics[i] = "null"
+		}
+	}
+
+	if last < 0 {
+		return nil
+	}
+	return topics[:last+1]
+}
+
+func (f topicFilter) match(topics []string) bool {
+	if len(f) > len(topics) {
+		for _, values := range f[len(topics):] {
+			if len(values) > 0 {
+				return false
+			}
+		}
+	}
+
+	for i, values := range f {
+		if len(values) == 0 || i >= len(topics) {
+			continue
+		}
+
+		topic := normalizeHex(topics[i])
+		if !utils.InStrSlice(values, topic) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterLogs drops logs which are not emitted by one of addresses or do not match topics,
+// searchlogs returns whole receipts and does not support every topic syntax
+func filterLogs(logs []eth.Log, addresses []string, topics topicFilter) []eth.Log {
+	normalizedAddresses := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		normalizedAddresses = append(normalizedAddresses, normalizeHex(addr))
+	}
+
+	filtered := make([]eth.Log, 0, len(logs))
+	for _, log := range logs {
+		if len(normalizedAddresses) > 0 && !utils.InStrSlice(normalizedAddresses, normalizeHex(log.Address)) {
+			continue
+		}
+		if !topics.match(log.Topics) {
+			continue
+		}
+		filtered = append(filtered, log)
+	}
+
+	return filtered
+}
+
+func normalizeHex(hex string) string {
+	return strings.ToLower(utils.RemoveHexPrefix(hex))
+}
@@ -0,0 +1,50 @@
+package transformer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTopicFilter(t *testing.T) {
+	a := "0x000000000000000000000000000000000000000000000000000000000000000a"
+	b := "0x000000000000000000000000000000000000000000000000000000000000000b"
+	c := "0x000000000000000000000000000000000000000000000000000000000000000c"
+
+	cases := []struct {
+		filter     string
+		topics     []string
+		want       bool
+		searchLogs []interface{}
+	}{
+		{`[]`, []string{a, b}, true, nil},
+		{`["` + a + `"]`, []string{a, b}, true, []interface{}{a[2:]}},
+		{`["` + b + `"]`, []string{a, b}, false, []interface{}{b[2:]}},
+		{`[null, "` + b + `"]`, []string{a, b}, true, []interface{}{"null", b[2:]}},
+		{`[null, "` + b + `"]`, []string{a}, false, []interface{}{"null", b[2:]}},
+		{`[["` + c + `", "` + a + `"]]`, []string{a, b}, true, nil},
+		{`[["` + c + `", "` + b + `"]]`, []string{a, b}, false, nil},
+		{`[["` + c + `", null], "` + b + `"]`, []string{a, b}, true, []interface{}{"null", b[2:]}},
+		{`[null, null, null]`, []string{a}, true, nil},
+	}
+
+	for _, c := range cases {
+		var ethtopics []interface{}
+		if err := json.Unmarshal([]byte(c.filter), &ethtopics); err != nil {
+			t.Fatal(err)
+		}
+
+		filter, err := newTopicFilter(ethtopics)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := filter.match(c.topics); got != c.want {
+			t.Errorf("filter: %s, topics: %v, want: %t, got: %t", c.filter, c.topics, c.want, got)
+		}
+
+		if got := filter.searchLogsTopics(); !reflect.DeepEqual(got, c.searchLogs) {
+			t.Errorf("filter: %s, want searchlogs topics: %v, got: %v", c.filter, c.searchLogs, got)
+		}
+	}
+}
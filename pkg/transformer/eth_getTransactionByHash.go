@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"context"
+	"log"
 	"math/big"
 
 	"fmt"
@@ -29,12 +31,12 @@ func (p *ProxyETHGetTransactionByHash) Request(rawreq *eth.JSONRPCRequest) (inte
 
 	qtumreq := p.ToRequest(&req)
 
-	return p.request(qtumreq)
+	return p.request(rawreq.Context(), qtumreq)
 }
 
-func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest) (*eth.GetTransactionByHashResponse, error) {
-	var tx *qtum.GetTransactionResponse
-	if err := p.Qtum.Request(qtum.MethodGetTransaction, req, &tx); err != nil {
+func (p *ProxyETHGetTransactionByHash) request(ctx context.Context, req *qtum.GetTransactionRequest) (*eth.GetTransactionByHashResponse, error) {
+	tx, err := p.GetTransaction(ctx, req.Txid)
+	if err != nil {
 		fmt.Println("err", err.Error())
 		if err == qtum.EmptyResponseErr {
 			return nil, nil
@@ -43,16 +45,26 @@ func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest)
 		return nil, err
 	}
 
-	ethVal, err := QtumAmountToEthValue(tx.Amount)
+	ethVal := QtumAmountToEthValue(p.Qtum, tx.Amount)
+
+	decodedRawTx, err := p.Qtum.DecodeRawTransaction(ctx, tx.Hex)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Qtum#DecodeRawTransaction")
 	}
 
-	decodedRawTx, err := p.Qtum.DecodeRawTransaction(tx.Hex)
+	ethTxResp, err := p.toEthTransaction(ctx, decodedRawTx, tx.Blockhash, &Nonces{Qtum: p.Qtum})
 	if err != nil {
-		return nil, errors.Wrap(err, "Qtum#DecodeRawTransaction")
+		return nil, err
 	}
+	ethTxResp.Value = ethVal
+
+	return ethTxResp, nil
+}
 
+// toEthTransaction converts a decoded Qtum transaction, the value is the amount sent to the contract if the
+// transaction has an OP_CALL or OP_CREATE output, block number, index and to are only known for such transactions.
+// nonces can be shared by the transactions of a block, so the sends of each sender are looked up once.
+func (p *ProxyETHGetTransactionByHash) toEthTransaction(ctx context.Context, decodedRawTx *qtum.DecodedRawTransactionResponse, blockHash string, nonces *Nonces) (*eth.GetTransactionByHashResponse, error) {
 	var gas, gasPrice, input string
 	type asmWithGasGasPriceEncodedABI interface {
 		CallData() string
@@ -60,7 +72,9 @@ func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest)
 		GasLimit() (*big.Int, error)
 	}
 
+	var err error
 	var asm asmWithGasGasPriceEncodedABI
+	var contractOut *qtum.DecodedRawTransactionOutV
 	for _, out := range decodedRawTx.Vout {
 		switch out.ScriptPubKey.Type {
 		case "call":
@@ -74,9 +88,11 @@ func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest)
 		default:
 			continue
 		}
+		contractOut = out
 		break
 	}
 
+	ethVal := "0x0"
 	if asm != nil {
 		input = utils.AddHexPrefix(asm.CallData())
 		gasLimitBigInt, err := asm.GasLimit()
@@ -88,13 +104,13 @@ func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest)
 			return nil, err
 		}
 		gas = hexutil.EncodeBig(gasLimitBigInt)
-		gasPrice = hexutil.EncodeBig(gasPriceBigInt)
+		gasPrice = hexutil.EncodeBig(p.SatoshisToWei(gasPriceBigInt))
+		ethVal = QtumAmountToEthValue(p.Qtum, contractOut.Value)
 	}
 
 	ethTxResp := eth.GetTransactionByHashResponse{
-		Hash:      utils.AddHexPrefix(tx.Txid),
-		BlockHash: utils.AddHexPrefix(tx.Blockhash),
-		Nonce:     "",
+		Hash:      utils.AddHexPrefix(decodedRawTx.Txid),
+		BlockHash: utils.AddHexPrefix(blockHash),
 		Value:     ethVal,
 		Input:     input,
 		Gas:       gas,
@@ -102,7 +118,7 @@ func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest)
 	}
 
 	if asm != nil {
-		receipt, err := p.Qtum.GetTransactionReceipt(tx.Txid)
+		receipt, err := p.Qtum.GetTransactionReceipt(ctx, decodedRawTx.Txid)
 		if err != nil && err != qtum.EmptyResponseErr {
 			return nil, err
 		}
@@ -114,6 +130,31 @@ func (p *ProxyETHGetTransactionByHash) request(req *qtum.GetTransactionRequest)
 		}
 	}
 
+	// the sender and the nonce need the indexes of qtumd, without them the transaction is returned with nonce 0
+	ethTxResp.Nonce = "0x0"
+	if ethTxResp.From == "" {
+		from, err := nonces.Sender(ctx, decodedRawTx)
+		if err != nil {
+			if !isQtumdError(err) {
+				return nil, err
+			}
+			log.Println("warning: cannot find the sender of transaction", decodedRawTx.Txid, "is qtumd running with -txindex?", err)
+		}
+		ethTxResp.From = from
+	}
+
+	if ethTxResp.From != "" {
+		nonce, err := nonces.TransactionNonce(ctx, utils.RemoveHexPrefix(ethTxResp.From), decodedRawTx.Txid)
+		switch {
+		case err == nil:
+			ethTxResp.Nonce = hexutil.EncodeUint64(nonce)
+		case isQtumdError(err):
+			log.Println("warning: cannot find the nonce of transaction", decodedRawTx.Txid, "is qtumd running with -addrindex?", err)
+		default:
+			return nil, err
+		}
+	}
+
 	return &ethTxResp, nil
 }
 
@@ -0,0 +1,55 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHGetTransactionCount implements ETHProxy
+type ProxyETHGetTransactionCount struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHGetTransactionCount) Method() string {
+	return "eth_getTransactionCount"
+}
+
+func (p *ProxyETHGetTransactionCount) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.GetTransactionCountRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), &req)
+}
+
+func (p *ProxyETHGetTransactionCount) request(ctx context.Context, req *eth.GetTransactionCountRequest) (eth.GetTransactionCountResponse, error) {
+	nonces := &Nonces{Qtum: p.Qtum}
+	addr := utils.RemoveHexPrefix(req.Address)
+
+	var count uint64
+	var err error
+	switch req.Tag {
+	case "pending":
+		count, err = nonces.PendingTransactionCount(ctx, addr)
+	case "latest":
+		count, err = nonces.TransactionCount(ctx, addr, -1)
+	case "earliest":
+		count, err = nonces.TransactionCount(ctx, addr, 0)
+	default:
+		height, decodeErr := utils.DecodeBig(req.Tag)
+		if decodeErr != nil {
+			return "", decodeErr
+		}
+		count, err = nonces.TransactionCount(ctx, addr, height.Int64())
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return eth.GetTransactionCountResponse(hexutil.EncodeUint64(count)), nil
+}
@@ -1,6 +1,9 @@
 package transformer
 
 import (
+	"context"
+	"encoding/hex"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
@@ -27,12 +30,12 @@ func (p *ProxyETHGetTransactionReceipt) Request(rawreq *eth.JSONRPCRequest) (int
 		return nil, err
 	}
 
-	return p.request(qtumreq)
+	return p.request(rawreq.Context(), qtumreq)
 }
 
-func (p *ProxyETHGetTransactionReceipt) request(req *qtum.GetTransactionReceiptRequest) (*eth.GetTransactionReceiptResponse, error) {
-	var receipt qtum.GetTransactionReceiptResponse
-	if err := p.Qtum.Request(qtum.MethodGetTransactionReceipt, req, &receipt); err != nil {
+func (p *ProxyETHGetTransactionReceipt) request(ctx context.Context, req *qtum.GetTransactionReceiptRequest) (*eth.GetTransactionReceiptResponse, error) {
+	receipt, err := p.GetTransactionReceipt(ctx, string(*req))
+	if err != nil {
 		if err == qtum.EmptyResponseErr {
 			return nil, nil
 		}
@@ -44,9 +47,14 @@ func (p *ProxyETHGetTransactionReceipt) request(req *qtum.GetTransactionReceiptR
 		status = "0x1"
 	}
 
-	r := qtum.TransactionReceiptStruct(receipt)
+	r := qtum.TransactionReceiptStruct(*receipt)
 	logs := getEthLogs(&r)
 
+	bloom, err := eth.LogsBloom(logs)
+	if err != nil {
+		return nil, err
+	}
+
 	ethTxReceipt := eth.GetTransactionReceiptResponse{
 		TransactionHash:   utils.AddHexPrefix(receipt.TransactionHash),
 		TransactionIndex:  hexutil.EncodeUint64(receipt.TransactionIndex),
@@ -56,15 +64,29 @@ func (p *ProxyETHGetTransactionReceipt) request(req *qtum.GetTransactionReceiptR
 		CumulativeGasUsed: hexutil.EncodeUint64(receipt.CumulativeGasUsed),
 		GasUsed:           hexutil.EncodeUint64(receipt.GasUsed),
 		Logs:              logs,
+		LogsBloom:         bloom.Hex(),
 		Status:            status,
-
-		// see Known issues
-		LogsBloom: "",
+	}
+	if receipt.Excepted == "Revert" {
+		ethTxReceipt.RevertReason = receiptRevertReason(receipt.ExceptedMessage)
 	}
 
 	return &ethTxReceipt, nil
 }
 
+// receiptRevertReason returns the reason of a revert from the exceptedMessage of a receipt, which qtumd leaves empty
+// or as the hex of the revert payload when it cannot decode it, e.g. for Panic(uint256)
+func receiptRevertReason(message string) string {
+	output, err := hex.DecodeString(utils.RemoveHexPrefix(message))
+	if err != nil || len(output) == 0 {
+		return message
+	}
+	if reason, ok := eth.DecodeRevertReason(output); ok {
+		return reason
+	}
+	return message
+}
+
 func (p *ProxyETHGetTransactionReceipt) ToRequest(ethreq *eth.GetTransactionReceiptRequest) (*qtum.GetTransactionReceiptRequest, error) {
 	qtumreq := qtum.GetTransactionReceiptRequest(utils.RemoveHexPrefix(string(*ethreq)))
 	return &qtumreq, nil
@@ -0,0 +1,33 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHHashrate implements ETHProxy
+type ProxyETHHashrate struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHHashrate) Method() string {
+	return "eth_hashrate"
+}
+
+func (p *ProxyETHHashrate) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+// request returns the hash rate of the network estimated by qtumd, a qtum node doesn't have its own
+func (p *ProxyETHHashrate) request(ctx context.Context) (*eth.HashrateResponse, error) {
+	qtumresp, err := p.GetMiningInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.HashrateResponse(hexutil.EncodeUint64(uint64(qtumresp.NetworkHashPS)))
+	return &resp, nil
+}
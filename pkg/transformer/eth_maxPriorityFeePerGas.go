@@ -0,0 +1,32 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHMaxPriorityFeePerGas implements ETHProxy
+type ProxyETHMaxPriorityFeePerGas struct {
+	*GasPriceOracle
+}
+
+func (p *ProxyETHMaxPriorityFeePerGas) Method() string {
+	return "eth_maxPriorityFeePerGas"
+}
+
+func (p *ProxyETHMaxPriorityFeePerGas) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+// request returns the suggested gas price, qtum has no base fee so the whole gas price goes to the staker
+func (p *ProxyETHMaxPriorityFeePerGas) request(ctx context.Context) (*eth.MaxPriorityFeePerGasResponse, error) {
+	price, err := p.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.MaxPriorityFeePerGasResponse(hexutil.EncodeBig(p.SatoshisToWei(price)))
+	return &resp, nil
+}
@@ -0,0 +1,32 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+)
+
+// ProxyETHMining implements ETHProxy
+type ProxyETHMining struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHMining) Method() string {
+	return "eth_mining"
+}
+
+func (p *ProxyETHMining) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+// request reports whether the wallet of qtumd is staking, qtum blocks are produced by proof of stake
+func (p *ProxyETHMining) request(ctx context.Context) (*eth.MiningResponse, error) {
+	qtumresp, err := p.GetStakingInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.MiningResponse(qtumresp.Staking)
+	return &resp, nil
+}
@@ -1,6 +1,8 @@
 package transformer
 
 import (
+	"strconv"
+
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
 )
@@ -18,22 +20,9 @@ func (p *ProxyETHNetVersion) Request(_ *eth.JSONRPCRequest) (interface{}, error)
 	return p.request()
 }
 
+// request returns the chain ID in decimal, like geth does for the networks whose network ID is the chain ID
+// https://github.com/ethereum/wiki/wiki/JSON-RPC#net_version
 func (p *ProxyETHNetVersion) request() (*eth.NetVersionResponse, error) {
-	var qtumresp *qtum.GetBlockChainInfoResponse
-	if err := p.Qtum.Request(qtum.MethodGetBlockChainInfo, nil, &qtumresp); err != nil {
-		return nil, err
-	}
-
-	var networkID string
-	switch qtumresp.Chain {
-	case "regtest":
-		// See: https://github.com/trufflesuite/ganache/issues/112 for an idea on how to generate an ID.
-		// https://github.com/ethereum/wiki/wiki/JSON-RPC#net_version
-		networkID = "0x1024"
-	default:
-		networkID = qtumresp.Chain
-	}
-
-	resp := eth.NetVersionResponse(networkID)
+	resp := eth.NetVersionResponse(strconv.FormatInt(p.ChainID(), 10))
 	return &resp, nil
 }
@@ -1,7 +1,7 @@
 package transformer
 
 import (
-	"log"
+	"context"
 
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
@@ -19,24 +19,20 @@ func (p *ProxyETHNewBlockFilter) Method() string {
 }
 
 func (p *ProxyETHNewBlockFilter) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
-	return p.request()
+	return p.request(rawreq.Context())
 }
 
-func (p *ProxyETHNewBlockFilter) request() (eth.NewBlockFilterResponse, error) {
-	blockCount, err := p.GetBlockCount()
+func (p *ProxyETHNewBlockFilter) request(ctx context.Context) (eth.NewBlockFilterResponse, error) {
+	blockCount, err := p.GetBlockCount(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	if p.Chain() == qtum.ChainRegTest {
-		defer func() {
-			if _, generateErr := p.Generate(1, nil); generateErr != nil {
-				log.Println("generate block err: ", generateErr)
-			}
-		}()
+	filter, err := p.filter.New(eth.ClientFromContext(ctx), eth.NewBlockFilterTy)
+	if err != nil {
+		return "", err
 	}
 
-	filter := p.filter.New(eth.NewBlockFilterTy)
 	filter.Data.Store("lastBlockNumber", blockCount.Uint64())
 
 	return eth.NewBlockFilterResponse(hexutil.EncodeUint64(filter.ID)), nil
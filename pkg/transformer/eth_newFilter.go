@@ -1,10 +1,9 @@
 package transformer
 
 import (
+	"context"
 	"encoding/json"
-
-	"github.com/dcb9/janus/pkg/utils"
-
+	"fmt"
 	"math/big"
 
 	"github.com/dcb9/go-ethereum/common/hexutil"
@@ -28,10 +27,10 @@ func (p *ProxyETHNewFilter) Request(rawreq *eth.JSONRPCRequest) (interface{}, er
 		return nil, err
 	}
 
-	return p.request(&req)
+	return p.request(rawreq.Context(), &req)
 }
 
-func (p *ProxyETHNewFilter) request(ethreq *eth.NewFilterRequest) (eth.NewFilterResponse, error) {
+func (p *ProxyETHNewFilter) request(ctx context.Context, ethreq *eth.NewFilterRequest) (eth.NewFilterResponse, error) {
 	var from *big.Int
 	var err error
 	if ethreq.FromBlock == nil {
@@ -43,28 +42,51 @@ func (p *ProxyETHNewFilter) request(ethreq *eth.NewFilterRequest) (eth.NewFilter
 		return "", err
 	}
 
-	filter := p.filter.New(eth.NewFilterTy, ethreq)
+	if err := p.checkBlockRange(ctx, from, ethreq.ToBlock); err != nil {
+		return "", err
+	}
+
+	topics, err := newTopicFilter(ethreq.Topics)
+	if err != nil {
+		return "", err
+	}
+
+	filter, err := p.filter.New(eth.ClientFromContext(ctx), eth.NewFilterTy, ethreq)
+	if err != nil {
+		return "", err
+	}
 	filter.Data.Store("lastBlockNumber", from.Uint64())
 
-	if len(ethreq.Topics) > 0 {
-		filter.Data.Store("topics", convertTopics(ethreq.Topics))
+	if len(topics) > 0 {
+		filter.Data.Store("topics", topics)
 	}
 
 	return eth.NewFilterResponse(hexutil.EncodeUint64(filter.ID)), nil
 }
 
-func convertTopics(ethtopics []interface{}) []interface{} {
-	var topics []interface{}
-	for _, topic := range ethtopics {
-		switch topic.(type) {
-		case []interface{}:
-			topics = append(topics, convertTopics(topic.([]interface{})))
-		case string:
-			topics = append(topics, utils.RemoveHexPrefix(topic.(string)))
-		case nil:
-			topics = append(topics, "null")
+// checkBlockRange rejects filters which would search more blocks than the limit of the filters from the block from,
+// like --max-log-block-range does for eth_getLogs
+func (p *ProxyETHNewFilter) checkBlockRange(ctx context.Context, from *big.Int, toBlock json.RawMessage) error {
+	max := p.filter.MaxLogBlockRange()
+	if max <= 0 || from.Sign() < 0 {
+		// unlimited, or from the latest block on
+		return nil
+	}
+
+	to, err := getQtumBlockNumber(toBlock, -1)
+	if err != nil {
+		return err
+	}
+	if to.Sign() < 0 {
+		blockCount, err := p.GetBlockCount(ctx)
+		if err != nil {
+			return err
 		}
+		to = blockCount.Int
 	}
 
-	return topics
+	if blocks := to.Int64() - from.Int64() + 1; blocks > max {
+		return eth.NewLimitExceededError(fmt.Sprintf("filters can search at most %d blocks, got %d", max, blocks))
+	}
+	return nil
 }
@@ -0,0 +1,53 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHNewPendingTransactionFilter implements ETHProxy
+type ProxyETHNewPendingTransactionFilter struct {
+	*qtum.Qtum
+	filter *eth.FilterSimulator
+}
+
+func (p *ProxyETHNewPendingTransactionFilter) Method() string {
+	return "eth_newPendingTransactionFilter"
+}
+
+func (p *ProxyETHNewPendingTransactionFilter) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+func (p *ProxyETHNewPendingTransactionFilter) request(ctx context.Context) (eth.NewPendingTransactionFilterResponse, error) {
+	filter, err := newPendingTransactionFilter(ctx, p.Qtum, p.filter)
+	if err != nil {
+		return "", err
+	}
+
+	return eth.NewPendingTransactionFilterResponse(hexutil.EncodeUint64(filter.ID)), nil
+}
+
+// newPendingTransactionFilter installs a filter which only reports transactions entering the mempool after now
+func newPendingTransactionFilter(ctx context.Context, q *qtum.Qtum, simulator *eth.FilterSimulator) (*eth.Filter, error) {
+	mempool, err := q.GetRawMempool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := simulator.New(eth.ClientFromContext(ctx), eth.NewPendingTransactionFilterTy)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(mempool))
+	for _, txid := range mempool {
+		seen[txid] = true
+	}
+	filter.Data.Store("mempool", seen)
+
+	return filter, nil
+}
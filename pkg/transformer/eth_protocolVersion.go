@@ -0,0 +1,33 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHProtocolVersion implements ETHProxy
+type ProxyETHProtocolVersion struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHProtocolVersion) Method() string {
+	return "eth_protocolVersion"
+}
+
+func (p *ProxyETHProtocolVersion) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+// request returns the P2P protocol version of qtumd
+func (p *ProxyETHProtocolVersion) request(ctx context.Context) (*eth.ProtocolVersionResponse, error) {
+	qtumresp, err := p.GetNetworkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.ProtocolVersionResponse(hexutil.EncodeUint64(uint64(qtumresp.ProtocolVersion)))
+	return &resp, nil
+}
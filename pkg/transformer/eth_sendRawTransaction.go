@@ -0,0 +1,250 @@
+package transformer
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// qtumd's default -minrelaytxfee, in satoshi per kB
+	rawTxFeeRate = 400000
+
+	// size of a P2PKH scriptSig (signature + compressed public key), added to the unsigned size of each input
+	rawTxScriptSigSize = 107
+
+	// change below this amount is left to the miner
+	rawTxDustThreshold = 3 * 182 * rawTxFeeRate / 1000
+)
+
+// ProxyETHSendRawTransaction implements ETHProxy
+//
+// The transaction is signed by an Ethereum key, which cannot sign a Qtum transaction.
+// The sender is recovered from the signature and its UTXOs are spent by an equivalent
+// Qtum transaction signed by qtumd, so the private key of the sender must be imported into qtumd's wallet.
+type ProxyETHSendRawTransaction struct {
+	*qtum.Qtum
+	miner *Miner
+
+	// serializes the nonce check and the relay of transactions, so a transaction cannot be relayed twice
+	// by concurrent requests before it is in the mempool
+	mutex sync.Mutex
+}
+
+func (p *ProxyETHSendRawTransaction) Method() string {
+	return "eth_sendRawTransaction"
+}
+
+func (p *ProxyETHSendRawTransaction) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.SendRawTransactionRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), &req)
+}
+
+func (p *ProxyETHSendRawTransaction) request(ctx context.Context, req *eth.SendRawTransactionRequest) (*eth.SendRawTransactionResponse, error) {
+	ethtx, err := eth.DecodeRawTransaction(string(*req))
+	if err != nil {
+		return nil, errors.Wrap(err, "eth#DecodeRawTransaction")
+	}
+
+	sender, err := ethtx.Sender()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// the nonces are read from the mempool of the node the transactions are sent to
+	ctx = qtum.WithWalletNode(ctx)
+	if err := p.checkReplay(ctx, ethtx, sender); err != nil {
+		return nil, err
+	}
+
+	qtumtx, err := p.ToTransaction(ctx, ethtx, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	unsigned, err := qtumtx.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := p.SignRawTransaction(ctx, hex.EncodeToString(unsigned))
+	if err != nil {
+		return nil, err
+	}
+	if !signed.Complete {
+		return nil, errors.Errorf("qtumd could not sign the transaction, is the private key of 0x%x imported?", sender)
+	}
+
+	defer p.miner.afterTransaction(ctx)
+
+	txid, err := p.SendRawTransaction(ctx, signed.Hex)
+	if err != nil {
+		return nil, err
+	}
+
+	ethresp := eth.SendRawTransactionResponse(utils.AddHexPrefix(string(txid)))
+	return &ethresp, nil
+}
+
+// checkReplay rejects transactions which could be relayed again by anyone who saw them: the transaction must be
+// protected by EIP-155 with the chain ID of the network, and its nonce must be the next one of the sender, see Nonces
+func (p *ProxyETHSendRawTransaction) checkReplay(ctx context.Context, ethtx *eth.RawTransaction, sender []byte) error {
+	chainID := ethtx.ChainID()
+	if chainID == nil {
+		return errors.New("only replay-protected (EIP-155) transactions are allowed")
+	}
+	if chainID.Cmp(big.NewInt(p.ChainID())) != 0 {
+		return eth.NewInvalidParamsError(fmt.Sprintf("invalid chain ID %s, the chain ID is %d", chainID, p.ChainID()))
+	}
+
+	nonces := &Nonces{Qtum: p.Qtum}
+	nonce, err := nonces.PendingTransactionCount(ctx, hex.EncodeToString(sender))
+	if err != nil {
+		return errors.Wrap(err, "nonce of the sender")
+	}
+
+	switch ethtx.Nonce.Cmp(new(big.Int).SetUint64(nonce)) {
+	case -1:
+		return errors.Errorf("nonce too low: address 0x%x, tx: %s state: %d", sender, ethtx.Nonce, nonce)
+	case 1:
+		// qtumd has no queue of future transactions
+		return errors.Errorf("nonce too high: address 0x%x, tx: %s state: %d", sender, ethtx.Nonce, nonce)
+	}
+
+	return nil
+}
+
+// ToTransaction builds the Qtum equivalent of ethtx, funded by the UTXOs of sender with the change returned to sender.
+// The first input belongs to sender, so sender is also msg.sender of an OP_CALL/OP_CREATE output.
+func (p *ProxyETHSendRawTransaction) ToTransaction(ctx context.Context, ethtx *eth.RawTransaction, sender []byte) (*qtum.Transaction, error) {
+	valueSatoshis, err := p.WeiToSatoshis(ethtx.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "value")
+	}
+	if !valueSatoshis.IsInt64() {
+		return nil, errors.New("value is too large")
+	}
+	value := valueSatoshis.Int64()
+
+	gasPrice, err := p.WeiToSatoshis(ethtx.GasPrice)
+	if err != nil {
+		return nil, errors.Wrap(err, "gas price")
+	}
+
+	qtumtx := qtum.NewTransaction()
+	// gas is paid by the inputs, unused gas is refunded to sender by qtumd
+	var gasFee *big.Int
+
+	isContract, err := p.isContractTransaction(ctx, ethtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if isContract {
+		gasFee = new(big.Int).Mul(ethtx.Gas, gasPrice)
+		if ethtx.IsCreateContract() {
+			qtumtx.AddOutput(value, qtum.ContractCreateScript(ethtx.Gas, gasPrice, ethtx.Data))
+		} else {
+			qtumtx.AddOutput(value, qtum.ContractCallScript(ethtx.Gas, gasPrice, ethtx.Data, ethtx.To))
+		}
+	} else {
+		gasFee = big.NewInt(0)
+		qtumtx.AddOutput(value, qtum.P2PKHScript(ethtx.To))
+	}
+
+	if !gasFee.IsInt64() {
+		return nil, errors.New("gas * gasPrice is too large")
+	}
+
+	senderBase58, err := p.HexToBase58Address(hex.EncodeToString(sender))
+	if err != nil {
+		return nil, err
+	}
+
+	utxos, err := p.ListUnspent(ctx, qtum.NewListUnspentRequest(senderBase58))
+	if err != nil {
+		return nil, err
+	}
+
+	required := value + gasFee.Int64()
+	funded := int64(0)
+	for _, utxo := range *utxos {
+		if !utxo.Spendable {
+			continue
+		}
+
+		qtumtx.AddInput(utxo.Txid, uint32(utxo.Vout))
+		funded += utxo.Amount.Satoshis().Int64()
+
+		fee, err := estimateRawTxFee(qtumtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if funded < required+fee {
+			continue
+		}
+
+		change := funded - required - fee
+		if change < rawTxDustThreshold {
+			return qtumtx, nil
+		}
+
+		qtumtx.AddOutput(change, qtum.P2PKHScript(sender))
+
+		// the change output makes the transaction bigger
+		feeWithChange, err := estimateRawTxFee(qtumtx)
+		if err != nil {
+			return nil, err
+		}
+
+		change -= feeWithChange - fee
+		if change < rawTxDustThreshold {
+			qtumtx.Outputs = qtumtx.Outputs[:len(qtumtx.Outputs)-1]
+		} else {
+			qtumtx.Outputs[len(qtumtx.Outputs)-1].Value = change
+		}
+
+		return qtumtx, nil
+	}
+
+	return nil, errors.Errorf("insufficient funds of 0x%x, %d satoshi are needed but only %d are spendable", sender, required, funded)
+}
+
+func (p *ProxyETHSendRawTransaction) isContractTransaction(ctx context.Context, ethtx *eth.RawTransaction) (bool, error) {
+	if ethtx.IsCreateContract() || len(ethtx.Data) > 0 {
+		return true, nil
+	}
+
+	// sending value without data to a contract calls its fallback function
+	qtumreq := qtum.GetAccountInfoRequest(hex.EncodeToString(ethtx.To))
+	if _, err := p.GetAccountInfo(ctx, &qtumreq); err == nil {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func estimateRawTxFee(tx *qtum.Transaction) (int64, error) {
+	unsigned, err := tx.Serialize()
+	if err != nil {
+		return 0, err
+	}
+
+	size := int64(len(unsigned) + len(tx.Inputs)*rawTxScriptSigSize)
+	return size * rawTxFeeRate / 1000, nil
+}
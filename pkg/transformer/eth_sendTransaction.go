@@ -1,7 +1,7 @@
 package transformer
 
 import (
-	"log"
+	"context"
 
 	"github.com/dcb9/janus/pkg/eth"
 	"github.com/dcb9/janus/pkg/qtum"
@@ -12,6 +12,8 @@ import (
 // ProxyETHSendTransaction implements ETHProxy
 type ProxyETHSendTransaction struct {
 	*qtum.Qtum
+	gasPrices *GasPriceOracle
+	miner     *Miner
 }
 
 func (p *ProxyETHSendTransaction) Method() string {
@@ -24,38 +26,29 @@ func (p *ProxyETHSendTransaction) Request(rawreq *eth.JSONRPCRequest) (interface
 		return nil, err
 	}
 
-	if p.Chain() == qtum.ChainRegTest {
-		defer func() {
-			if _, generateErr := p.Generate(1, nil); generateErr != nil {
-				log.Println("generate block err: ", generateErr)
-			}
-		}()
-	}
+	ctx := rawreq.Context()
+	defer p.miner.afterTransaction(ctx)
 
 	if req.IsCreateContract() {
-		return p.requestCreateContract(&req)
+		return p.requestCreateContract(ctx, &req)
 	} else if req.IsSendEther() {
-		return p.requestSendToAddress(&req)
+		return p.requestSendToAddress(ctx, &req)
 	} else if req.IsCallContract() {
-		return p.requestSendToContract(&req)
+		return p.requestSendToContract(ctx, &req)
 	}
 
 	return nil, errors.New("Unknown operation")
 }
 
-func (p *ProxyETHSendTransaction) requestSendToContract(ethtx *eth.SendTransactionRequest) (*eth.SendTransactionResponse, error) {
-	gasLimit, gasPrice, err := EthGasToQtum(ethtx)
+func (p *ProxyETHSendTransaction) requestSendToContract(ctx context.Context, ethtx *eth.SendTransactionRequest) (*eth.SendTransactionResponse, error) {
+	gasLimit, gasPrice, err := p.gasPrices.EthGasToQtum(ctx, ethtx)
 	if err != nil {
 		return nil, err
 	}
 
-	amount := 0.0
-	if ethtx.Value != "" {
-		var err error
-		amount, err = EthValueToQtumAmount(ethtx.Value)
-		if err != nil {
-			return nil, errors.Wrap(err, "EthValueToQtumAmount:")
-		}
+	amount, err := EthValueToQtumAmount(p.Qtum, ethtx.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "EthValueToQtumAmount:")
 	}
 
 	qtumreq := qtum.SendToContractRequest{
@@ -67,7 +60,7 @@ func (p *ProxyETHSendTransaction) requestSendToContract(ethtx *eth.SendTransacti
 	}
 
 	if from := ethtx.From; from != "" && utils.IsEthHexAddress(from) {
-		from, err = p.FromHexAddress(from)
+		from, err = p.HexToBase58Address(from)
 		if err != nil {
 			return nil, err
 		}
@@ -75,7 +68,7 @@ func (p *ProxyETHSendTransaction) requestSendToContract(ethtx *eth.SendTransacti
 	}
 
 	var resp *qtum.SendToContractResponse
-	if err := p.Qtum.Request(qtum.MethodSendToContract, &qtumreq, &resp); err != nil {
+	if err := p.Qtum.Request(ctx, qtum.MethodSendToContract, &qtumreq, &resp); err != nil {
 		return nil, err
 	}
 
@@ -83,10 +76,10 @@ func (p *ProxyETHSendTransaction) requestSendToContract(ethtx *eth.SendTransacti
 	return &ethresp, nil
 }
 
-func (p *ProxyETHSendTransaction) requestSendToAddress(req *eth.SendTransactionRequest) (*eth.SendTransactionResponse, error) {
+func (p *ProxyETHSendTransaction) requestSendToAddress(ctx context.Context, req *eth.SendTransactionRequest) (*eth.SendTransactionResponse, error) {
 	getQtumWalletAddress := func(addr string) (string, error) {
 		if utils.IsEthHexAddress(addr) {
-			return p.FromHexAddress(utils.RemoveHexPrefix(addr))
+			return p.HexToBase58Address(utils.RemoveHexPrefix(addr))
 		}
 		return addr, nil
 	}
@@ -101,7 +94,7 @@ func (p *ProxyETHSendTransaction) requestSendToAddress(req *eth.SendTransactionR
 		return nil, err
 	}
 
-	amount, err := EthValueToQtumAmount(req.Value)
+	amount, err := EthValueToQtumAmount(p.Qtum, req.Value)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +106,7 @@ func (p *ProxyETHSendTransaction) requestSendToAddress(req *eth.SendTransactionR
 	}
 
 	var qtumresp qtum.SendToAddressResponse
-	if err := p.Qtum.Request(qtum.MethodSendToAddress, &qtumreq, &qtumresp); err != nil {
+	if err := p.Qtum.Request(ctx, qtum.MethodSendToAddress, &qtumreq, &qtumresp); err != nil {
 		return nil, err
 	}
 
@@ -122,8 +115,8 @@ func (p *ProxyETHSendTransaction) requestSendToAddress(req *eth.SendTransactionR
 	return &ethresp, nil
 }
 
-func (p *ProxyETHSendTransaction) requestCreateContract(req *eth.SendTransactionRequest) (*eth.SendTransactionResponse, error) {
-	gasLimit, gasPrice, err := EthGasToQtum(req)
+func (p *ProxyETHSendTransaction) requestCreateContract(ctx context.Context, req *eth.SendTransactionRequest) (*eth.SendTransactionResponse, error) {
+	gasLimit, gasPrice, err := p.gasPrices.EthGasToQtum(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +130,7 @@ func (p *ProxyETHSendTransaction) requestCreateContract(req *eth.SendTransaction
 	if req.From != "" {
 		from := req.From
 		if utils.IsEthHexAddress(from) {
-			from, err = p.FromHexAddress(from)
+			from, err = p.HexToBase58Address(from)
 			if err != nil {
 				return nil, err
 			}
@@ -147,7 +140,7 @@ func (p *ProxyETHSendTransaction) requestCreateContract(req *eth.SendTransaction
 	}
 
 	var resp *qtum.CreateContractResponse
-	if err := p.Qtum.Request(qtum.MethodCreateContract, qtumreq, &resp); err != nil {
+	if err := p.Qtum.Request(ctx, qtum.MethodCreateContract, qtumreq, &resp); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,321 @@
+package transformer
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+const (
+	SubscriptionNewHeads               = "newHeads"
+	SubscriptionLogs                   = "logs"
+	SubscriptionNewPendingTransactions = "newPendingTransactions"
+)
+
+// Notifier delivers eth_subscription notifications to the client which owns the subscriptions
+type Notifier interface {
+	Notify(*eth.JSONRPCNotification) error
+}
+
+// Subscriptions keeps the eth_subscribe subscriptions of a single client connection.
+//
+// Qtum has no push API, so every subscription polls qtumd in its own goroutine,
+// the same way eth_getFilterChanges does for filters.
+type Subscriptions struct {
+	*qtum.Qtum
+	transformer *Transformer
+	notifier    Notifier
+	logger      log.Logger
+	interval    time.Duration
+
+	filter  *eth.FilterSimulator
+	changes *ProxyETHGetFilterChanges
+	blocks  *ProxyETHGetBlockByNumber
+
+	mutex  sync.Mutex
+	subs   map[string]*subscription
+	closed bool
+}
+
+type subscription struct {
+	// cancels the polling goroutine and its calls to qtumd
+	cancel context.CancelFunc
+
+	// the filter polled by the subscription, nil if it does not use one
+	filter *eth.Filter
+}
+
+type pollFunc func(ctx context.Context) ([]interface{}, error)
+
+// NewSubscriptions creates the subscriptions of a client connection, the caller must call Close when the connection is gone
+func (t *Transformer) NewSubscriptions(n Notifier) *Subscriptions {
+	// the filters of the subscriptions are installed with the other filters, so they count in the filters of the client
+	changes, ok := t.transformers["eth_getFilterChanges"].(*ProxyETHGetFilterChanges)
+	if !ok {
+		changes = &ProxyETHGetFilterChanges{Qtum: t.qtumClient, filter: eth.NewFilterSimulator()}
+	}
+
+	// the heads are built like eth_getBlockByNumber, with its cache of the DGP parameters
+	blocks, ok := t.transformers["eth_getBlockByNumber"].(*ProxyETHGetBlockByNumber)
+	if !ok {
+		blocks = &ProxyETHGetBlockByNumber{Qtum: t.qtumClient, gasPrices: NewGasPriceOracle(t.qtumClient)}
+	}
+
+	return &Subscriptions{
+		Qtum:        t.qtumClient,
+		transformer: t,
+		notifier:    n,
+		logger:      t.logger,
+		interval:    t.subscriptionInterval,
+		filter:      changes.filter,
+		changes:     changes,
+		blocks:      blocks,
+		subs:        make(map[string]*subscription),
+	}
+}
+
+// Transform handles eth_subscribe and eth_unsubscribe, other methods are passed to the Transformer
+func (s *Subscriptions) Transform(rpcReq *eth.JSONRPCRequest) (interface{}, error) {
+	var result interface{}
+	var err error
+
+	switch rpcReq.Method {
+	case "eth_subscribe":
+		var req eth.EthSubscriptionRequest
+		if err = unmarshalRequest(rpcReq.Params, &req); err == nil {
+			result, err = s.subscribe(rpcReq.Context(), &req)
+		}
+	case "eth_unsubscribe":
+		var req eth.EthUnsubscribeRequest
+		if err = unmarshalRequest(rpcReq.Params, &req); err == nil {
+			result, err = s.unsubscribe(&req)
+		}
+	default:
+		return s.transformer.Transform(rpcReq)
+	}
+	if err != nil {
+		return nil, toJSONRPCError(err)
+	}
+
+	return result, nil
+}
+
+// Close cancels all subscriptions
+func (s *Subscriptions) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, sub := range s.subs {
+		s.cancel(id, sub)
+	}
+	s.closed = true
+}
+
+// subscribe starts polling, ctx is only used by the first calls to qtumd, the subscription lives until it is canceled
+func (s *Subscriptions) subscribe(ctx context.Context, req *eth.EthSubscriptionRequest) (eth.EthSubscriptionResponse, error) {
+	var poll pollFunc
+	var filter *eth.Filter
+	var err error
+
+	switch req.Type {
+	case SubscriptionNewHeads:
+		poll, filter, err = s.newHeadsPoller(ctx)
+	case SubscriptionLogs:
+		poll, filter, err = s.logsPoller(ctx, req.Params)
+	case SubscriptionNewPendingTransactions:
+		poll, filter, err = s.newPendingTransactionsPoller(ctx)
+	default:
+		return "", eth.NewInvalidParamsError(fmt.Sprintf("Unsupported subscription type %s", req.Type))
+	}
+	if err != nil {
+		return "", err
+	}
+	// the filter is polled by the subscription, not by the client, and uninstalled when the subscription ends
+	filter.Pin()
+
+	id, err := newSubscriptionID()
+	if err != nil {
+		return "", err
+	}
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	sub := &subscription{
+		cancel: cancel,
+		filter: filter,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		s.cancel(id, sub)
+		return "", errors.New("Connection is closed")
+	}
+	s.subs[id] = sub
+
+	go s.run(pollCtx, id, poll)
+
+	return eth.EthSubscriptionResponse(id), nil
+}
+
+func (s *Subscriptions) unsubscribe(req *eth.EthUnsubscribeRequest) (eth.EthUnsubscribeResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sub, ok := s.subs[string(*req)]
+	if !ok {
+		return false, nil
+	}
+
+	s.cancel(string(*req), sub)
+
+	return true, nil
+}
+
+// cancel must be called with s.mutex held
+func (s *Subscriptions) cancel(id string, sub *subscription) {
+	sub.cancel()
+	if sub.filter != nil {
+		s.filter.Uninstall(sub.filter.ID)
+	}
+	delete(s.subs, id)
+}
+
+// remove cancels the subscription id if it was not canceled yet
+func (s *Subscriptions) remove(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if sub, ok := s.subs[id]; ok {
+		s.cancel(id, sub)
+	}
+}
+
+func (s *Subscriptions) run(ctx context.Context, id string, poll pollFunc) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		results, err := poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			level.Error(s.logger).Log("msg", "poll subscription", "subscription", id, "err", err.Error())
+			continue
+		}
+
+		for _, result := range results {
+			notification, err := eth.NewJSONRPCNotification("eth_subscription", &eth.EthSubscription{
+				SubscriptionID: id,
+				Result:         result,
+			})
+			if err != nil {
+				level.Error(s.logger).Log("msg", "marshal notification", "subscription", id, "err", err.Error())
+				continue
+			}
+
+			if err := s.notifier.Notify(notification); err != nil {
+				level.Error(s.logger).Log("msg", "notify subscription", "subscription", id, "err", err.Error())
+				s.remove(id)
+				return
+			}
+		}
+	}
+}
+
+func (s *Subscriptions) newHeadsPoller(ctx context.Context) (pollFunc, *eth.Filter, error) {
+	blockCount, err := s.GetBlockCount(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := s.filter.New(eth.ClientFromContext(ctx), eth.NewBlockFilterTy)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter.Data.Store("lastBlockNumber", blockCount.Uint64())
+
+	return func(ctx context.Context) ([]interface{}, error) {
+		hashes, err := s.changes.requestBlockFilter(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		heads := make([]interface{}, 0, len(hashes))
+		for _, hash := range hashes {
+			head, err := s.blocks.blockByHash(ctx, utils.RemoveHexPrefix(hash.(string)), false)
+			if err != nil {
+				return nil, err
+			}
+			heads = append(heads, head)
+		}
+
+		return heads, nil
+	}, filter, nil
+}
+
+func (s *Subscriptions) logsPoller(ctx context.Context, params *eth.EthLogSubscriptionParameter) (pollFunc, *eth.Filter, error) {
+	blockCount, err := s.GetBlockCount(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ethreq := &eth.NewFilterRequest{}
+	if params != nil {
+		ethreq.Address = params.Address
+		ethreq.Topics = params.Topics
+	}
+
+	topics, err := newTopicFilter(ethreq.Topics)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := s.filter.New(eth.ClientFromContext(ctx), eth.NewFilterTy, ethreq)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter.Data.Store("lastBlockNumber", blockCount.Uint64())
+	if len(topics) > 0 {
+		filter.Data.Store("topics", topics)
+	}
+
+	return func(ctx context.Context) ([]interface{}, error) {
+		return s.changes.requestFilter(ctx, filter)
+	}, filter, nil
+}
+
+func (s *Subscriptions) newPendingTransactionsPoller(ctx context.Context) (pollFunc, *eth.Filter, error) {
+	filter, err := newPendingTransactionFilter(ctx, s.Qtum, s.filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return func(ctx context.Context) ([]interface{}, error) {
+		return s.changes.requestPendingTransactionFilter(ctx, filter)
+	}, filter, nil
+}
+
+func newSubscriptionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hexutil.Encode(id), nil
+}
@@ -0,0 +1,55 @@
+package transformer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHSyncing implements ETHProxy
+type ProxyETHSyncing struct {
+	*qtum.Qtum
+
+	mutex   sync.Mutex
+	syncing bool
+	// the first block seen while syncing
+	startingBlock int64
+}
+
+func (p *ProxyETHSyncing) Method() string {
+	return "eth_syncing"
+}
+
+func (p *ProxyETHSyncing) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+// request returns false if qtumd is synced, otherwise the progress of the sync
+func (p *ProxyETHSyncing) request(ctx context.Context) (interface{}, error) {
+	qtumresp, err := p.GetBlockChainInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !qtumresp.InitialBlockDownload && qtumresp.Blocks >= qtumresp.Headers {
+		p.syncing = false
+		return false, nil
+	}
+
+	if !p.syncing {
+		p.syncing = true
+		p.startingBlock = qtumresp.Blocks
+	}
+
+	return &eth.SyncingResult{
+		StartingBlock: hexutil.EncodeUint64(uint64(p.startingBlock)),
+		CurrentBlock:  hexutil.EncodeUint64(uint64(qtumresp.Blocks)),
+		HighestBlock:  hexutil.EncodeUint64(uint64(qtumresp.Headers)),
+	}, nil
+}
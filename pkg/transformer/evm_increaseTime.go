@@ -0,0 +1,46 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dcb9/janus/pkg/eth"
+)
+
+// ProxyEVMIncreaseTime implements ETHProxy
+type ProxyEVMIncreaseTime struct {
+	*Miner
+}
+
+func (p *ProxyEVMIncreaseTime) Method() string {
+	return "evm_increaseTime"
+}
+
+func (p *ProxyEVMIncreaseTime) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.EVMIncreaseTimeRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), req)
+}
+
+func (p *ProxyEVMIncreaseTime) request(ctx context.Context, req eth.EVMIncreaseTimeRequest) (eth.EVMIncreaseTimeResponse, error) {
+	seconds, err := decodeQuantity(json.RawMessage(req))
+	if err != nil {
+		return 0, eth.NewInvalidParamsError(fmt.Sprintf("invalid seconds: %s", err))
+	}
+	if seconds > math.MaxInt64/uint64(time.Second) {
+		return 0, eth.NewInvalidParamsError("too many seconds")
+	}
+
+	total, err := p.IncreaseTime(ctx, time.Duration(seconds)*time.Second)
+	if err != nil {
+		return 0, err
+	}
+
+	return eth.EVMIncreaseTimeResponse(total / time.Second), nil
+}
@@ -0,0 +1,59 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dcb9/janus/pkg/eth"
+)
+
+// maxMineBlocks is the maximum number of blocks evm_mine generates at once
+const maxMineBlocks = 1000
+
+// ProxyEVMMine implements ETHProxy
+type ProxyEVMMine struct {
+	*Miner
+}
+
+func (p *ProxyEVMMine) Method() string {
+	return "evm_mine"
+}
+
+func (p *ProxyEVMMine) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.EVMMineRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), &req)
+}
+
+func (p *ProxyEVMMine) request(ctx context.Context, req *eth.EVMMineRequest) (eth.EVMMineResponse, error) {
+	blocks := uint64(1)
+	if len(req.Blocks) > 0 {
+		var err error
+		if blocks, err = decodeQuantity(req.Blocks); err != nil {
+			return "", eth.NewInvalidParamsError(fmt.Sprintf("invalid blocks: %s", err))
+		}
+		if blocks == 0 || blocks > maxMineBlocks {
+			return "", eth.NewInvalidParamsError(fmt.Sprintf("blocks must be between 1 and %d", maxMineBlocks))
+		}
+	}
+
+	if len(req.Timestamp) > 0 {
+		timestamp, err := decodeQuantity(req.Timestamp)
+		if err != nil {
+			return "", eth.NewInvalidParamsError(fmt.Sprintf("invalid timestamp: %s", err))
+		}
+		if err := p.SetTime(ctx, time.Unix(int64(timestamp), 0)); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := p.Mine(ctx, int(blocks)); err != nil {
+		return "", err
+	}
+
+	return "0x0", nil
+}
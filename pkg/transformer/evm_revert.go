@@ -0,0 +1,41 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dcb9/janus/pkg/eth"
+)
+
+// ProxyEVMRevert implements ETHProxy
+type ProxyEVMRevert struct {
+	*Miner
+}
+
+func (p *ProxyEVMRevert) Method() string {
+	return "evm_revert"
+}
+
+func (p *ProxyEVMRevert) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	var req eth.EVMRevertRequest
+	if err := unmarshalRequest(rawreq.Params, &req); err != nil {
+		return nil, err
+	}
+
+	return p.request(rawreq.Context(), req)
+}
+
+func (p *ProxyEVMRevert) request(ctx context.Context, req eth.EVMRevertRequest) (eth.EVMRevertResponse, error) {
+	id, err := decodeQuantity(json.RawMessage(req))
+	if err != nil {
+		return false, eth.NewInvalidParamsError(fmt.Sprintf("invalid snapshot id: %s", err))
+	}
+
+	reverted, err := p.Revert(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return eth.EVMRevertResponse(reverted), nil
+}
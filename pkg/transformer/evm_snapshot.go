@@ -0,0 +1,30 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyEVMSnapshot implements ETHProxy
+type ProxyEVMSnapshot struct {
+	*Miner
+}
+
+func (p *ProxyEVMSnapshot) Method() string {
+	return "evm_snapshot"
+}
+
+func (p *ProxyEVMSnapshot) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+func (p *ProxyEVMSnapshot) request(ctx context.Context) (eth.EVMSnapshotResponse, error) {
+	id, err := p.Snapshot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return eth.EVMSnapshotResponse(hexutil.EncodeUint64(id)), nil
+}
@@ -0,0 +1,283 @@
+package transformer
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// how many recent blocks the suggested gas price is based on
+	gasPriceBlocks = 20
+	// the suggested gas price is this percentile of the gas prices paid in the recent blocks
+	gasPricePercentile = 60
+	// how long the DGP parameters of qtumd are cached, they only change by governance
+	dgpInfoTTL = time.Minute
+	// how old the suggested gas price gets before it is refreshed in the background
+	gasPriceTTL = 10 * time.Second
+)
+
+// GasPriceOracle suggests gas prices, in satoshis per gas, from the minimum gas price of the network and
+// the gas prices paid by the contract transactions of recent blocks, like the gas price oracle of geth
+type GasPriceOracle struct {
+	*qtum.Qtum
+
+	mutex sync.Mutex
+	// the percentile of the gas prices paid up to the tip with hash lastTip, nil if none was paid
+	lastTip  string
+	lastPaid *big.Int
+	// the last suggestion, made at priceTime
+	lastPrice  *big.Int
+	priceTime  time.Time
+	refreshing bool
+	dgpInfo    *qtum.GetDGPInfoResponse
+	dgpTime    time.Time
+}
+
+func NewGasPriceOracle(q *qtum.Qtum) *GasPriceOracle {
+	return &GasPriceOracle{Qtum: q}
+}
+
+// contractGas is the gas price and gas used of an OP_CALL or OP_CREATE output
+type contractGas struct {
+	price   *big.Int
+	gasUsed uint64
+}
+
+// DGPInfo returns the minimum gas price and the block gas limit of the network, see getdgpinfo
+func (o *GasPriceOracle) DGPInfo(ctx context.Context) (*qtum.GetDGPInfoResponse, error) {
+	o.mutex.Lock()
+	if o.dgpInfo != nil && time.Since(o.dgpTime) < dgpInfoTTL {
+		defer o.mutex.Unlock()
+		return o.dgpInfo, nil
+	}
+	o.mutex.Unlock()
+
+	dgpInfo, err := o.GetDGPInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	o.dgpInfo, o.dgpTime = dgpInfo, time.Now()
+	o.mutex.Unlock()
+
+	return dgpInfo, nil
+}
+
+// BlockGasLimit returns the gas limit of the block at height. qtumd only reports the current DGP parameters,
+// so it is the current block gas limit for every block.
+func (o *GasPriceOracle) BlockGasLimit(ctx context.Context, height int64) (uint64, error) {
+	dgpInfo, err := o.DGPInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return dgpInfo.BlockGasLimit, nil
+}
+
+// SuggestGasPrice returns the gasPricePercentile-th percentile of the gas prices paid in the last
+// gasPriceBlocks blocks, or the minimum gas price of the network if it is higher. Only the first suggestion
+// is made on the request path, the later ones are refreshed in the background once they are gasPriceTTL old.
+func (o *GasPriceOracle) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	o.mutex.Lock()
+	price := o.lastPrice
+	if price != nil && time.Since(o.priceTime) >= gasPriceTTL && !o.refreshing {
+		o.refreshing = true
+		go o.refresh()
+	}
+	o.mutex.Unlock()
+
+	if price != nil {
+		return new(big.Int).Set(price), nil
+	}
+	return o.suggestGasPrice(ctx)
+}
+
+func (o *GasPriceOracle) refresh() {
+	if _, err := o.suggestGasPrice(context.Background()); err != nil {
+		log.Println("refresh gas price err: ", err)
+	}
+
+	o.mutex.Lock()
+	o.refreshing = false
+	o.mutex.Unlock()
+}
+
+// suggestGasPrice makes a suggestion, the minimum gas price of the network if the recent blocks cannot be scanned
+func (o *GasPriceOracle) suggestGasPrice(ctx context.Context) (*big.Int, error) {
+	dgpInfo, err := o.DGPInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	price := new(big.Int).SetUint64(dgpInfo.MinGasPrice)
+
+	paid, err := o.paidGasPrice(ctx)
+	if err != nil {
+		log.Println("scan gas prices err: ", err)
+	} else if paid != nil && paid.Cmp(price) > 0 {
+		price = paid
+	}
+
+	o.mutex.Lock()
+	o.lastPrice, o.priceTime = price, time.Now()
+	o.mutex.Unlock()
+
+	return new(big.Int).Set(price), nil
+}
+
+// paidGasPrice returns the gasPricePercentile-th percentile of the gas prices paid in the last gasPriceBlocks blocks,
+// nil if none was paid, the blocks are only scanned again when the tip changes
+func (o *GasPriceOracle) paidGasPrice(ctx context.Context) (*big.Int, error) {
+	blockCount, err := o.GetBlockCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := o.GetBlockHash(ctx, blockCount.Int)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	if o.lastTip == string(tip) {
+		defer o.mutex.Unlock()
+		return o.lastPaid, nil
+	}
+	o.mutex.Unlock()
+
+	var prices []*big.Int
+	for n := blockCount.Int64(); n >= 0 && n > blockCount.Int64()-gasPriceBlocks; n-- {
+		outputs, err := o.blockGas(ctx, big.NewInt(n), false)
+		if err != nil {
+			return nil, err
+		}
+		for _, out := range outputs {
+			prices = append(prices, out.price)
+		}
+	}
+
+	var paid *big.Int
+	if len(prices) > 0 {
+		sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+		paid = prices[(len(prices)-1)*gasPricePercentile/100]
+	}
+
+	o.mutex.Lock()
+	o.lastTip, o.lastPaid = string(tip), paid
+	o.mutex.Unlock()
+
+	return paid, nil
+}
+
+// blockGas returns the contract outputs of the block, sorted by gas price. Their gas used is only set if
+// withGasUsed, it comes from the receipts, which qtumd only has with -logevents.
+func (o *GasPriceOracle) blockGas(ctx context.Context, number *big.Int, withGasUsed bool) ([]contractGas, error) {
+	hash, err := o.GetBlockHash(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	block, err := o.GetBlockWithTransactions(ctx, string(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []contractGas
+	for _, tx := range block.Tx {
+		if !isContractTx(tx) {
+			continue
+		}
+
+		// qtumd has a receipt per contract output, in the order of the outputs
+		var receipts []qtum.TransactionReceiptStruct
+		if withGasUsed {
+			receipts, err = o.GetTransactionReceipts(ctx, tx.Txid)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		i := 0
+		for _, out := range tx.Vout {
+			var price *big.Int
+			switch out.ScriptPubKey.Type {
+			case "call":
+				asm, err := qtum.ParseCallASM(out.ScriptPubKey.Asm)
+				if err != nil {
+					return nil, err
+				}
+				price, err = asm.GasPrice()
+				if err != nil {
+					return nil, err
+				}
+			case "create":
+				asm, err := qtum.ParseCreateASM(out.ScriptPubKey.Asm)
+				if err != nil {
+					return nil, err
+				}
+				price, err = asm.GasPrice()
+				if err != nil {
+					return nil, err
+				}
+			default:
+				continue
+			}
+
+			gas := contractGas{price: price}
+			if i < len(receipts) {
+				gas.gasUsed = receipts[i].GasUsed
+			}
+			outputs = append(outputs, gas)
+			i++
+		}
+	}
+
+	sort.SliceStable(outputs, func(i, j int) bool { return outputs[i].price.Cmp(outputs[j].price) < 0 })
+	return outputs, nil
+}
+
+// EthGasToQtum returns the gas limit and gas price of g, the block gas limit and the suggested gas price if they are not set
+func (o *GasPriceOracle) EthGasToQtum(ctx context.Context, g EthGas) (gasLimit *big.Int, gasPrice qtum.Amount, err error) {
+	gasLimit, err = o.EthGasLimit(ctx, g)
+	if err != nil {
+		return
+	}
+
+	if price := g.GasPriceHex(); price != "" {
+		gasPrice, err = EthValueToQtumAmount(o.Qtum, price)
+		if err != nil {
+			err = errors.Wrap(err, "decode gas price")
+		}
+		return
+	}
+
+	suggested, err := o.SuggestGasPrice(ctx)
+	if err != nil {
+		return
+	}
+	gasPrice = qtum.Amount{Int: suggested}
+
+	return
+}
+
+// EthGasLimit returns the gas limit of g, the block gas limit if it is not set
+func (o *GasPriceOracle) EthGasLimit(ctx context.Context, g EthGas) (*big.Int, error) {
+	if gas := g.GasHex(); gas != "" {
+		gasLimit, err := utils.DecodeBig(gas)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode gas")
+		}
+		return gasLimit, nil
+	}
+
+	dgpInfo, err := o.DGPInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(dgpInfo.BlockGasLimit), nil
+}
@@ -0,0 +1,284 @@
+package transformer
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/pkg/errors"
+)
+
+// excludeFeeDelta is subtracted from the fees of the transactions of reverted blocks, so qtumd never mines them again
+const excludeFeeDelta = -1e16
+
+// Miner mines the blocks of a regtest chain, after each transaction (automine), every interval or on evm_mine,
+// and implements the evm_* methods of Ganache which control the chain
+type Miner struct {
+	*qtum.Qtum
+
+	// mine a block after each transaction sent through janus
+	automine bool
+	// mine a block every interval, 0 disables it
+	interval time.Duration
+
+	mutex sync.Mutex
+	// how far the clock of qtumd is ahead of the system clock, see evm_increaseTime
+	timeOffset time.Duration
+	snapshots  []snapshot
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// snapshot is the state of the chain evm_snapshot saves and evm_revert goes back to
+type snapshot struct {
+	height     int64
+	hash       string
+	timeOffset time.Duration
+	// the transactions in the mempool when the snapshot was taken
+	mempool map[string]bool
+}
+
+type MinerOption func(*Miner)
+
+// SetAutomine mines a block after each transaction, true by default
+func SetAutomine(automine bool) MinerOption {
+	return func(m *Miner) {
+		m.automine = automine
+	}
+}
+
+// SetMiningInterval mines a block every interval once Start is called, 0 disables it
+func SetMiningInterval(interval time.Duration) MinerOption {
+	return func(m *Miner) {
+		m.interval = interval
+	}
+}
+
+func NewMiner(q *qtum.Qtum, opts ...MinerOption) *Miner {
+	m := &Miner{
+		Qtum:     q,
+		automine: true,
+		stop:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Start mines a block every interval until Stop is called, it does nothing if interval mining is disabled
+func (m *Miner) Start() {
+	if m.interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := m.Mine(context.Background(), 1); err != nil {
+					log.Println("generate block err: ", err)
+				}
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (m *Miner) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Mine generates n blocks, with the clock of qtumd moved by evm_increaseTime
+func (m *Miner) Mine(ctx context.Context, n int) (qtum.GenerateResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.timeOffset != 0 {
+		// the mock time of qtumd stands still, move it along with the system clock
+		if err := m.syncTime(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return m.Generate(ctx, n, nil)
+}
+
+// afterTransaction mines a block if automine is on, m can be nil
+func (m *Miner) afterTransaction(ctx context.Context) {
+	if m == nil || !m.automine || m.Chain() != qtum.ChainRegTest {
+		return
+	}
+
+	if _, err := m.Mine(ctx, 1); err != nil {
+		log.Println("generate block err: ", err)
+	}
+}
+
+// syncTime sets the clock of qtumd to the system clock plus the time offset, the mutex must be held
+func (m *Miner) syncTime(ctx context.Context) error {
+	if m.timeOffset == 0 {
+		return m.SetMockTime(ctx, 0)
+	}
+	return m.SetMockTime(ctx, time.Now().Add(m.timeOffset).Unix())
+}
+
+// IncreaseTime moves the clock of qtumd forward and returns the total time it was moved
+func (m *Miner) IncreaseTime(ctx context.Context, d time.Duration) (time.Duration, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.timeOffset += d
+	if err := m.syncTime(ctx); err != nil {
+		m.timeOffset -= d
+		return 0, err
+	}
+	return m.timeOffset, nil
+}
+
+// SetTime sets the clock of qtumd to t, the next blocks are mined from t on
+func (m *Miner) SetTime(ctx context.Context, t time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	offset := m.timeOffset
+	m.timeOffset = time.Until(t).Truncate(time.Second)
+	if err := m.syncTime(ctx); err != nil {
+		m.timeOffset = offset
+		return err
+	}
+	return nil
+}
+
+// Snapshot saves the state of the chain and returns the id evm_revert goes back to it with, ids start at 1
+func (m *Miner) Snapshot(ctx context.Context) (uint64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	blockCount, err := m.GetBlockCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	hash, err := m.GetBlockHash(ctx, blockCount.Int)
+	if err != nil {
+		return 0, err
+	}
+	mempool, err := m.GetRawMempool(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s := snapshot{
+		height:     blockCount.Int64(),
+		hash:       string(hash),
+		timeOffset: m.timeOffset,
+		mempool:    make(map[string]bool, len(mempool)),
+	}
+	for _, txid := range mempool {
+		s.mempool[txid] = true
+	}
+	m.snapshots = append(m.snapshots, s)
+
+	return uint64(len(m.snapshots)), nil
+}
+
+// Revert goes back to the state of the snapshot id, which is deleted with all the later snapshots like Ganache does.
+// The blocks mined since are invalidated and their transactions, and the ones sent since, are never mined again,
+// but they stay in the mempool of qtumd, with their inputs spent, as it cannot evict them.
+// It returns false if there is no snapshot id.
+func (m *Miner) Revert(ctx context.Context, id uint64) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if id == 0 || id > uint64(len(m.snapshots)) {
+		return false, nil
+	}
+	s := m.snapshots[id-1]
+
+	blockCount, err := m.GetBlockCount(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// the transactions which were not in the mempool at the snapshot, they go back to the mempool when their blocks are invalidated
+	var txids []string
+	excluded := make(map[string]bool)
+	exclude := func(txid string) {
+		if !s.mempool[txid] && !excluded[txid] {
+			excluded[txid] = true
+			txids = append(txids, txid)
+		}
+	}
+	if blockCount.Int64() > s.height {
+		for n := s.height + 1; n <= blockCount.Int64(); n++ {
+			hash, err := m.GetBlockHash(ctx, big.NewInt(n))
+			if err != nil {
+				return false, err
+			}
+			block, err := m.GetBlock(ctx, string(hash))
+			if err != nil {
+				return false, err
+			}
+			// the first transaction is the coinbase
+			for i, txid := range block.Tx {
+				if i > 0 {
+					exclude(txid)
+				}
+			}
+		}
+
+		first, err := m.GetBlockHash(ctx, big.NewInt(s.height+1))
+		if err != nil {
+			return false, err
+		}
+		if err := m.InvalidateBlock(ctx, string(first)); err != nil {
+			return false, err
+		}
+		m.PurgeCache()
+
+		tip, err := m.GetBlockHash(ctx, big.NewInt(s.height))
+		if err != nil {
+			return false, err
+		}
+		if string(tip) != s.hash {
+			// the chain was reorganized since the snapshot, it cannot be reverted
+			if err := m.ReconsiderBlock(ctx, string(first)); err != nil {
+				log.Println("reconsider block err: ", err)
+			}
+			m.PurgeCache()
+			return false, errors.Errorf("block %d is not the block of the snapshot anymore", s.height)
+		}
+	}
+
+	mempool, err := m.GetRawMempool(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, txid := range mempool {
+		exclude(txid)
+	}
+	for _, txid := range txids {
+		if err := m.PrioritiseTransaction(ctx, &qtum.PrioritiseTransactionRequest{Txid: txid, FeeDelta: excludeFeeDelta}); err != nil {
+			return false, err
+		}
+	}
+
+	m.snapshots = m.snapshots[:id-1]
+	m.timeOffset = s.timeOffset
+	if err := m.syncTime(ctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+)
+
+// ProxyETHNetListening implements ETHProxy
+type ProxyETHNetListening struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHNetListening) Method() string {
+	return "net_listening"
+}
+
+func (p *ProxyETHNetListening) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+func (p *ProxyETHNetListening) request(ctx context.Context) (*eth.NetListeningResponse, error) {
+	qtumresp, err := p.GetNetworkInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.NetListeningResponse(qtumresp.NetworkActive)
+	return &resp, nil
+}
@@ -0,0 +1,32 @@
+package transformer
+
+import (
+	"context"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ProxyETHNetPeerCount implements ETHProxy
+type ProxyETHNetPeerCount struct {
+	*qtum.Qtum
+}
+
+func (p *ProxyETHNetPeerCount) Method() string {
+	return "net_peerCount"
+}
+
+func (p *ProxyETHNetPeerCount) Request(rawreq *eth.JSONRPCRequest) (interface{}, error) {
+	return p.request(rawreq.Context())
+}
+
+func (p *ProxyETHNetPeerCount) request(ctx context.Context) (*eth.NetPeerCountResponse, error) {
+	qtumresp, err := p.GetConnectionCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := eth.NetPeerCountResponse(hexutil.EncodeUint64(uint64(qtumresp)))
+	return &resp, nil
+}
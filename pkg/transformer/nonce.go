@@ -0,0 +1,183 @@
+package transformer
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dcb9/janus/pkg/qtum"
+	"github.com/dcb9/janus/pkg/utils"
+)
+
+// Nonces emulates the nonces of Ethereum accounts, which Qtum doesn't have.
+//
+// The nonce of a transaction is the number of transactions sent by the same address before it,
+// a transaction is sent by an address if it spends one of its UTXOs. Confirmed transactions are
+// ordered by block height and position in the block, so nonces are the same on every node,
+// transactions in the mempool follow in the order they were received.
+//
+// The sends of an address are found with the address index, so qtumd must run with -addrindex.
+// The sends are looked up once per address, so a Nonces is only used by one request.
+type Nonces struct {
+	*qtum.Qtum
+
+	// the sends of each hex address, see sends
+	sendsByAddress map[string][]string
+}
+
+// TransactionCount returns the number of transactions sent by hexAddress up to and including the block height,
+// a negative height is the latest block
+func (n *Nonces) TransactionCount(ctx context.Context, hexAddress string, height int64) (uint64, error) {
+	base58Address, err := n.HexToBase58Address(hexAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	sends, err := n.confirmedSends(ctx, base58Address, height)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(sends)), nil
+}
+
+// PendingTransactionCount returns the number of transactions sent by hexAddress, including the ones in the mempool
+func (n *Nonces) PendingTransactionCount(ctx context.Context, hexAddress string) (uint64, error) {
+	sends, err := n.sends(ctx, hexAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(sends)), nil
+}
+
+// TransactionNonce returns the nonce of the transaction txid sent by hexAddress
+func (n *Nonces) TransactionNonce(ctx context.Context, hexAddress, txid string) (uint64, error) {
+	sends, err := n.sends(ctx, hexAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, send := range sends {
+		if send == txid {
+			return uint64(i), nil
+		}
+	}
+
+	// the transaction is unknown to the index yet, it will be the next one
+	return uint64(len(sends)), nil
+}
+
+// Sender returns the hex address owning the output spent by the first input of tx,
+// the sender of a coinbase transaction is an empty string
+func (n *Nonces) Sender(ctx context.Context, tx *qtum.DecodedRawTransactionResponse) (string, error) {
+	if len(tx.Vin) == 0 || tx.Vin[0].Txid == "" {
+		return "", nil
+	}
+
+	prevTx, err := n.GetRawTransaction(ctx, tx.Vin[0].Txid)
+	if err != nil {
+		return "", err
+	}
+
+	for _, out := range prevTx.Vout {
+		if out.N != tx.Vin[0].Vout || len(out.ScriptPubKey.Addresses) == 0 {
+			continue
+		}
+
+		hexAddress, err := n.Base58ToHexAddress(out.ScriptPubKey.Addresses[0])
+		if err != nil {
+			return "", err
+		}
+		return utils.AddHexPrefix(hexAddress), nil
+	}
+
+	return "", nil
+}
+
+// sends returns the txids of the confirmed and pending transactions sent by hexAddress, ordered by nonce
+func (n *Nonces) sends(ctx context.Context, hexAddress string) ([]string, error) {
+	if sends, ok := n.sendsByAddress[hexAddress]; ok {
+		return sends, nil
+	}
+
+	sends, err := n.lookupSends(ctx, hexAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.sendsByAddress == nil {
+		n.sendsByAddress = make(map[string][]string)
+	}
+	n.sendsByAddress[hexAddress] = sends
+	return sends, nil
+}
+
+func (n *Nonces) lookupSends(ctx context.Context, hexAddress string) ([]string, error) {
+	base58Address, err := n.HexToBase58Address(hexAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	sends, err := n.confirmedSends(ctx, base58Address, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas, err := n.GetAddressMempool(ctx, &qtum.GetAddressMempoolRequest{Addresses: []string{base58Address}})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(deltas, func(i, j int) bool {
+		if deltas[i].Timestamp != deltas[j].Timestamp {
+			return deltas[i].Timestamp < deltas[j].Timestamp
+		}
+		return deltas[i].Txid < deltas[j].Txid
+	})
+
+	seen := make(map[string]bool, len(sends))
+	for _, txid := range sends {
+		seen[txid] = true
+	}
+	for _, delta := range deltas {
+		if delta.Satoshis >= 0 || seen[delta.Txid] {
+			continue
+		}
+		seen[delta.Txid] = true
+		sends = append(sends, delta.Txid)
+	}
+
+	return sends, nil
+}
+
+// confirmedSends returns the txids of the transactions sent by base58Address in chain order,
+// up to and including the block height, a negative height is the latest block
+func (n *Nonces) confirmedSends(ctx context.Context, base58Address string, height int64) ([]string, error) {
+	deltas, err := n.GetAddressDeltas(ctx, &qtum.GetAddressDeltasRequest{Addresses: []string{base58Address}})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(deltas, func(i, j int) bool {
+		if deltas[i].Height != deltas[j].Height {
+			return deltas[i].Height < deltas[j].Height
+		}
+		return deltas[i].BlockIndex < deltas[j].BlockIndex
+	})
+
+	var sends []string
+	seen := make(map[string]bool)
+	for _, delta := range deltas {
+		if height >= 0 && delta.Height > height {
+			break
+		}
+		// a transaction spending several UTXOs of the address has a delta for each of them
+		if delta.Satoshis >= 0 || seen[delta.Txid] {
+			continue
+		}
+		seen[delta.Txid] = true
+		sends = append(sends, delta.Txid)
+	}
+
+	return sends, nil
+}
@@ -0,0 +1,123 @@
+package transformer
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/utils"
+)
+
+// reorgWindow is how many of the last delivered blocks a filter remembers to detect reorgs
+const reorgWindow = 64
+
+// deliveredBlock is a block whose hash or logs were delivered by a filter
+type deliveredBlock struct {
+	number uint64
+	hash   string
+	// the logs of the block which were delivered, they are delivered again as removed if the block is orphaned
+	logs []eth.Log
+}
+
+func filterDeliveredBlocks(filter *eth.Filter) []deliveredBlock {
+	blocks, ok := filter.Data.Load("blocks")
+	if !ok {
+		return nil
+	}
+	return blocks.([]deliveredBlock)
+}
+
+// storeDeliveredBlocks remembers the last reorgWindow delivered blocks, oldest first
+func storeDeliveredBlocks(filter *eth.Filter, blocks []deliveredBlock) {
+	if len(blocks) > reorgWindow {
+		blocks = blocks[len(blocks)-reorgWindow:]
+	}
+	filter.Data.Store("blocks", blocks)
+}
+
+// rewind drops the delivered blocks which are not on the main chain anymore, so the next poll starts after the
+// last delivered block which still is. It returns the number of that block and the orphaned blocks, oldest first.
+// A chain shorter than the delivered blocks whose hashes all match is a node which is behind, e.g. another upstream,
+// nothing is orphaned and the filter waits for the next poll.
+func (p *ProxyETHGetFilterChanges) rewind(ctx context.Context, filter *eth.Filter, lastBlockNumber, blockCount uint64) (uint64, []deliveredBlock, error) {
+	blocks := filterDeliveredBlocks(filter)
+
+	i := len(blocks) - 1
+	for ; i >= 0; i-- {
+		b := blocks[i]
+		if b.number > blockCount {
+			// orphaned only if a block below is
+			continue
+		}
+
+		// cached hashes are not updated by reorgs
+		hash, err := p.GetBlockHashUncached(ctx, new(big.Int).SetUint64(b.number))
+		if err != nil {
+			return 0, nil, err
+		}
+		if string(hash) == b.hash {
+			break
+		}
+	}
+
+	orphaned := blocks[i+1:]
+	if len(orphaned) == 0 || orphaned[0].number > blockCount {
+		return lastBlockNumber, nil, nil
+	}
+
+	storeDeliveredBlocks(filter, blocks[:i+1])
+	return orphaned[0].number - 1, orphaned, nil
+}
+
+// removedLogs returns the logs of orphaned blocks marked as removed, the most recent first like geth does
+func removedLogs(orphaned []deliveredBlock) []interface{} {
+	var logs []interface{}
+	for i := len(orphaned) - 1; i >= 0; i-- {
+		for j := len(orphaned[i].logs) - 1; j >= 0; j-- {
+			log := orphaned[i].logs[j]
+			log.Removed = true
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
+// deliverBlocks remembers the blocks from+1 to blockCount which are in the reorg window, with their logs.
+// The blocks with logs are remembered by the hash of their logs, which is the block the logs were found in,
+// the hashes of the others are looked up.
+func (p *ProxyETHGetFilterChanges) deliverBlocks(ctx context.Context, filter *eth.Filter, from, blockCount uint64, logs []eth.Log) error {
+	if blockCount > reorgWindow && from < blockCount-reorgWindow {
+		from = blockCount - reorgWindow
+	}
+
+	withLogs := make(map[uint64]*deliveredBlock)
+	for _, log := range logs {
+		number, err := utils.DecodeBig(log.BlockNumber)
+		if err != nil {
+			return err
+		}
+		b, ok := withLogs[number.Uint64()]
+		if !ok {
+			b = &deliveredBlock{number: number.Uint64(), hash: utils.RemoveHexPrefix(log.BlockHash)}
+			withLogs[b.number] = b
+		}
+		b.logs = append(b.logs, log)
+	}
+
+	blocks := filterDeliveredBlocks(filter)
+	for number := from + 1; number <= blockCount; number++ {
+		if b, ok := withLogs[number]; ok {
+			blocks = append(blocks, *b)
+			continue
+		}
+
+		hash, err := p.GetBlockHashUncached(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, deliveredBlock{number: number, hash: string(hash)})
+	}
+	storeDeliveredBlocks(filter, blocks)
+
+	return nil
+}
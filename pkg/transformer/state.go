@@ -0,0 +1,55 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/qtum"
+)
+
+// checkStateBlock returns an error unless ethBlock, the block parameter of a method which reads the state of
+// contracts or accounts, is the latest block. qtumd only serves the latest state, so the state of past blocks
+// is unavailable rather than silently replaced by the latest one.
+func checkStateBlock(ctx context.Context, q *qtum.Qtum, ethBlock json.RawMessage) error {
+	if ethBlock == nil || string(ethBlock) == "null" {
+		return nil
+	}
+
+	if isString(ethBlock) {
+		var tag string
+		if err := json.Unmarshal(ethBlock, &tag); err != nil {
+			return eth.NewInvalidParamsError(err.Error())
+		}
+		switch tag {
+		case "latest", "pending":
+			// qtumd runs calls against the state of the tip, the pending transactions are not applied
+			return nil
+		case "earliest":
+			ethBlock = json.RawMessage("0")
+		}
+	}
+
+	number, err := getQtumBlockNumber(ethBlock, -1)
+	if err != nil {
+		return eth.NewInvalidParamsError(err.Error())
+	}
+	if number.Sign() < 0 {
+		return nil
+	}
+
+	blockCount, err := q.GetBlockCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch number.Cmp(blockCount.Int) {
+	case 0:
+		return nil
+	case 1:
+		return eth.NewInvalidParamsError(fmt.Sprintf("block %s not found, the latest block is %s", number, blockCount.Int))
+	default:
+		return eth.NewResourceUnavailableError(fmt.Sprintf("historical state unavailable, qtumd only serves the state of the latest block %s, not of block %s", blockCount.Int, number))
+	}
+}
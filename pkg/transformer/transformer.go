@@ -1,17 +1,33 @@
 package transformer
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/dcb9/janus/pkg/eth"
+	"github.com/dcb9/janus/pkg/metrics"
 	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/go-kit/kit/log"
 	"github.com/pkg/errors"
 )
 
+// unsupportedMethod is the method label of requests for unknown methods, so clients cannot create arbitrary series
+const unsupportedMethod = "unsupported"
+
+var (
+	requestsTotal      = metrics.NewCounterVec("janus_requests_total", "Ethereum JSON-RPC requests by method", "method")
+	requestErrorsTotal = metrics.NewCounterVec("janus_request_errors_total", "Ethereum JSON-RPC requests which failed, by method and error code", "method", "code")
+	requestDuration    = metrics.NewHistogramVec("janus_request_duration_seconds", "Latency of Ethereum JSON-RPC requests by method", metrics.DefaultBuckets, "method")
+)
+
 type Transformer struct {
 	qtumClient   *qtum.Qtum
 	debugMode    bool
 	logger       log.Logger
 	transformers map[string]ETHProxy
+
+	// how often eth_subscribe subscriptions poll qtumd
+	subscriptionInterval time.Duration
 }
 
 func New(qtumClient *qtum.Qtum, proxies []ETHProxy, opts ...Option) (*Transformer, error) {
@@ -20,8 +36,9 @@ func New(qtumClient *qtum.Qtum, proxies []ETHProxy, opts ...Option) (*Transforme
 	}
 
 	t := &Transformer{
-		qtumClient: qtumClient,
-		logger:     log.NewNopLogger(),
+		qtumClient:           qtumClient,
+		logger:               log.NewNopLogger(),
+		subscriptionInterval: time.Second,
 	}
 
 	var err error
@@ -58,25 +75,48 @@ func (t *Transformer) Register(p ETHProxy) error {
 func (t *Transformer) Transform(rpcReq *eth.JSONRPCRequest) (interface{}, error) {
 	p, err := t.getProxy(rpcReq)
 	if err != nil {
+		observeRequest(unsupportedMethod, time.Now(), err)
 		return nil, err
 	}
 
-	return p.Request(rpcReq)
+	start := time.Now()
+	result, err := p.Request(rpcReq)
+	if err != nil {
+		jsonErr := toJSONRPCError(err)
+		observeRequest(rpcReq.Method, start, jsonErr)
+		return nil, jsonErr
+	}
+	observeRequest(rpcReq.Method, start, nil)
+
+	return result, nil
+}
+
+func observeRequest(method string, start time.Time, err error) {
+	requestsTotal.Inc(method)
+	requestDuration.Observe(time.Since(start).Seconds(), method)
+	if err != nil {
+		requestErrorsTotal.Inc(method, strconv.Itoa(eth.ToJSONRPCError(err).Code))
+	}
 }
 
 func (t *Transformer) getProxy(rpcReq *eth.JSONRPCRequest) (ETHProxy, error) {
 	m := rpcReq.Method
 	p, ok := t.transformers[m]
 	if !ok {
-		return nil, errors.Errorf("Unsupported method %s", m)
+		return nil, eth.NewMethodNotFoundError(m)
 	}
 	return p, nil
 }
 
-func DefaultProxies(qtumRPCClient *qtum.Qtum) []ETHProxy {
-	filter := eth.NewFilterSimulator()
+// DefaultProxies returns the proxies of the eth_* methods, miner mines a block after each transaction if automine is on,
+// it can be nil
+func DefaultProxies(qtumRPCClient *qtum.Qtum, miner *Miner, filterOpts ...eth.FilterOption) []ETHProxy {
+	filter := eth.NewFilterSimulator(filterOpts...)
 	getFilterChanges := &ProxyETHGetFilterChanges{Qtum: qtumRPCClient, filter: filter}
-	ethCall := &ProxyETHCall{Qtum: qtumRPCClient}
+	gasPrices := NewGasPriceOracle(qtumRPCClient)
+	ethCall := &ProxyETHCall{Qtum: qtumRPCClient, gasPrices: gasPrices}
+	getBlockByNumber := &ProxyETHGetBlockByNumber{Qtum: qtumRPCClient, gasPrices: gasPrices}
+	accounts := &ProxyETHAccounts{Qtum: qtumRPCClient}
 
 	return []ETHProxy{
 		ethCall,
@@ -86,20 +126,51 @@ func DefaultProxies(qtumRPCClient *qtum.Qtum) []ETHProxy {
 		&ProxyETHGetTransactionByHash{Qtum: qtumRPCClient},
 		&ProxyETHGetLogs{Qtum: qtumRPCClient},
 		&ProxyETHGetTransactionReceipt{Qtum: qtumRPCClient},
-		&ProxyETHSendTransaction{Qtum: qtumRPCClient},
-		&ProxyETHAccounts{Qtum: qtumRPCClient},
+		&ProxyETHSendTransaction{Qtum: qtumRPCClient, gasPrices: gasPrices, miner: miner},
+		&ProxyETHSendRawTransaction{Qtum: qtumRPCClient, miner: miner},
+		accounts,
 		&ProxyETHGetCode{Qtum: qtumRPCClient},
 
 		&ProxyETHNewFilter{Qtum: qtumRPCClient, filter: filter},
 		&ProxyETHNewBlockFilter{Qtum: qtumRPCClient, filter: filter},
+		&ProxyETHNewPendingTransactionFilter{Qtum: qtumRPCClient, filter: filter},
 		getFilterChanges,
 		&ProxyETHGetFilterLogs{ProxyETHGetFilterChanges: getFilterChanges},
 		&ProxyETHUninstallFilter{Qtum: qtumRPCClient, filter: filter},
 
 		&ProxyETHEstimateGas{ProxyETHCall: ethCall},
-		&ProxyETHGetBlockByNumber{Qtum: qtumRPCClient},
+		&ProxyETHGasPrice{GasPriceOracle: gasPrices},
+		&ProxyETHMaxPriorityFeePerGas{GasPriceOracle: gasPrices},
+		&ProxyETHFeeHistory{GasPriceOracle: gasPrices},
+		getBlockByNumber,
+		&ProxyETHGetBlockByHash{ProxyETHGetBlockByNumber: getBlockByNumber},
 		&ProxyETHGetBalance{Qtum: qtumRPCClient},
+		&ProxyETHGetTransactionCount{Qtum: qtumRPCClient},
 		&Web3ClientVersion{},
+
+		&ProxyETHChainID{Qtum: qtumRPCClient},
+		&ProxyETHNetListening{Qtum: qtumRPCClient},
+		&ProxyETHNetPeerCount{Qtum: qtumRPCClient},
+		&ProxyETHSyncing{Qtum: qtumRPCClient},
+		&ProxyETHProtocolVersion{Qtum: qtumRPCClient},
+		&ProxyETHMining{Qtum: qtumRPCClient},
+		&ProxyETHHashrate{Qtum: qtumRPCClient},
+		&ProxyETHCoinbase{ProxyETHAccounts: accounts},
+	}
+}
+
+// DevProxies returns the proxies of the evm_* methods of Ganache. The methods which change the chain are only
+// returned on regtest, on other chains there is only evm_snapshot.
+func DevProxies(miner *Miner) []ETHProxy {
+	if miner.Chain() != qtum.ChainRegTest {
+		return []ETHProxy{&ProxyEVMSnapshot{Miner: miner}}
+	}
+
+	return []ETHProxy{
+		&ProxyEVMMine{Miner: miner},
+		&ProxyEVMSnapshot{Miner: miner},
+		&ProxyEVMRevert{Miner: miner},
+		&ProxyEVMIncreaseTime{Miner: miner},
 	}
 }
 
@@ -116,3 +187,13 @@ func SetLogger(l log.Logger) func(*Transformer) error {
 		return nil
 	}
 }
+
+func SetSubscriptionInterval(interval time.Duration) func(*Transformer) error {
+	return func(t *Transformer) error {
+		if interval <= 0 {
+			return errors.New("subscription interval must be positive")
+		}
+		t.subscriptionInterval = interval
+		return nil
+	}
+}
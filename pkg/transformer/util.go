@@ -2,9 +2,9 @@ package transformer
 
 import (
 	"encoding/json"
-	"fmt"
 	"math/big"
 
+	"github.com/dcb9/janus/pkg/qtum"
 	"github.com/dcb9/janus/pkg/utils"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
@@ -15,63 +15,67 @@ type EthGas interface {
 	GasPriceHex() string
 }
 
-func EthGasToQtum(g EthGas) (gasLimit *big.Int, gasPrice string, err error) {
-	gasLimit = big.NewInt(40000000)
-	if gas := g.GasHex(); gas != "" {
-		gasLimit, err = utils.DecodeBig(gas)
-		if err != nil {
-			err = errors.Wrap(err, "decode gas")
-			return
-		}
+// EthValueToQtumAmount converts a hex amount of wei into satoshis, an empty value is 0
+func EthValueToQtumAmount(q *qtum.Qtum, val string) (qtum.Amount, error) {
+	if val == "" {
+		return qtum.NewAmount(0), nil
 	}
 
-	gasPriceFloat64, err := EthValueToQtumAmount(g.GasPriceHex())
+	wei, err := utils.DecodeBig(val)
 	if err != nil {
-		return nil, "0.0", err
+		return qtum.Amount{}, err
 	}
-	gasPrice = fmt.Sprintf("%.8f", gasPriceFloat64)
 
-	return
+	satoshis, err := q.WeiToSatoshis(wei)
+	if err != nil {
+		return qtum.Amount{}, err
+	}
+	return qtum.Amount{Int: satoshis}, nil
 }
 
-func EthValueToQtumAmount(val string) (float64, error) {
-	if val == "" {
-		return 0.0000004, nil
-	}
+// QtumAmountToEthValue converts satoshis into a hex amount of wei, the amounts of the wallet of qtumd are
+// negative for the transactions it sent, values are positive
+func QtumAmountToEthValue(q *qtum.Qtum, amount qtum.Amount) string {
+	satoshis := new(big.Int).Abs(amount.Satoshis())
+	return hexutil.EncodeBig(q.SatoshisToWei(satoshis))
+}
 
-	ethVal, err := utils.DecodeBig(val)
-	if err != nil {
-		return 0.0, err
+func unmarshalRequest(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.Wrap(UnmarshalRequestErr, err.Error())
 	}
+	return nil
+}
 
-	ethValFloat64 := new(big.Float)
-	ethValFloat64, success := ethValFloat64.SetString(ethVal.String())
-	if !success {
-		return 0.0, errors.New("big.Float#SetString is not success")
+// decodeQuantity decodes a quantity encoded as a hex string or a number
+func decodeQuantity(v json.RawMessage) (uint64, error) {
+	if len(v) > 0 && isString(v) {
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return 0, err
+		}
+		return hexutil.DecodeUint64(s)
 	}
 
-	amount := ethValFloat64.Mul(ethValFloat64, big.NewFloat(float64(1e-8)))
-	result, _ := amount.Float64()
-
-	return result, nil
+	var n uint64
+	err := json.Unmarshal(v, &n)
+	return n, err
 }
 
-func QtumAmountToEthValue(amount float64) (string, error) {
-	bigAmount := big.NewFloat(amount)
-	bigAmount = bigAmount.Mul(bigAmount, big.NewFloat(float64(1e8)))
-
-	result := new(big.Int)
-	result, success := result.SetString(bigAmount.String(), 10)
-	if !success {
-		return "0x0", errors.New("big.Int#SetString is not success")
+// isBlockTag returns whether the block parameter v is one of tags
+func isBlockTag(v json.RawMessage, tags ...string) bool {
+	if len(v) == 0 || !isString(v) {
+		return false
 	}
 
-	return hexutil.EncodeBig(result), nil
-}
-
-func unmarshalRequest(data []byte, v interface{}) error {
-	if err := json.Unmarshal(data, v); err != nil {
-		return errors.Wrap(UnmarshalRequestErr, err.Error())
+	var tag string
+	if err := json.Unmarshal(v, &tag); err != nil {
+		return false
 	}
-	return nil
+	for _, t := range tags {
+		if tag == t {
+			return true
+		}
+	}
+	return false
 }
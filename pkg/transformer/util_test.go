@@ -1,41 +1,60 @@
 package transformer
 
 import (
+	"math/big"
 	"testing"
+
+	"github.com/dcb9/janus/pkg/qtum"
 )
 
 func TestEthValueToQtumAmount(t *testing.T) {
-	cases := []map[string]interface{}{
-		{
-			"in":   "0x64",
-			"want": float64(0.000001),
-		},
-		{
-
-			"in":   "0x1",
-			"want": 0.00000001,
-		},
+	cases := []struct {
+		in            string
+		weiPerSatoshi int64
+		want          string
+	}{
+		{"0x64", 1, "0.00000100"},
+		{"0x1", 1, "0.00000001"},
+		{"", 1, "0.00000000"},
+		{"0x2540be400", 1e10, "0.00000001"},
+		// 1 QTUM
+		{"0xde0b6b3a7640000", 1e10, "1.00000000"},
+		// 1 QTUM + 1 satoshi, float64 rounds it away
+		{"0xde0b6b5fb6fe400", 1e10, "1.00000001"},
 	}
+
 	for _, c := range cases {
-		in := c["in"].(string)
-		want := c["want"].(float64)
-		got, err := EthValueToQtumAmount(in)
+		q, err := qtum.New(&qtum.Client{}, qtum.ChainRegTest, qtum.SetWeiPerSatoshi(big.NewInt(c.weiPerSatoshi)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := EthValueToQtumAmount(q, c.in)
 		if err != nil {
 			t.Error(err)
+			continue
 		}
-		if got != want {
-			t.Errorf("in: %s, want: %f, got: %f", in, want, got)
+		if got.String() != c.want {
+			t.Errorf("in: %s, want: %s, got: %s", c.in, c.want, got)
+		}
+
+		if c.in == "" {
+			continue
+		}
+		if back := QtumAmountToEthValue(q, got); back != c.in {
+			t.Errorf("in: %s, want: %s, got: %s", got, c.in, back)
 		}
 	}
 }
 
 func TestQtumAmountToEthValue(t *testing.T) {
-	in, want := 0.000001, "0x64"
-	got, err := QtumAmountToEthValue(in)
+	q, err := qtum.New(&qtum.Client{}, qtum.ChainRegTest)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if got != want {
-		t.Errorf("in: %f, want: %s, got: %s", in, want, got)
+
+	in, want := qtum.NewAmount(-100), "0x64"
+	if got := QtumAmountToEthValue(q, in); got != want {
+		t.Errorf("in: %s, want: %s, got: %s", in, want, got)
 	}
 }